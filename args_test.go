@@ -1,9 +1,6 @@
 package gocli
 
-import (
-	"os"
-	"testing"
-)
+import "testing"
 
 func TestExactArgs(t *testing.T) {
 	tests := []struct {
@@ -156,6 +153,37 @@ func TestOnlyValidArgs(t *testing.T) {
 	}
 }
 
+func TestWithOnlyValidArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantError bool
+	}{
+		{"valid", []string{"start"}, false},
+		{"invalid", []string{"pause"}, true},
+		{"valid but too many", []string{"start", "stop"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewCommand(
+				WithName("test"),
+				WithAllowedArgs("start", "stop"),
+				WithOnlyValidArgs(),
+				WithArgValidator(MaximumNArgs(1)),
+				WithRun(func(cmd *Command, args []string) error { return nil }),
+			)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if (err != nil) != tt.wantError {
+				t.Errorf("Execute() with args %v: error = %v, wantError %v",
+					tt.args, err, tt.wantError)
+			}
+		})
+	}
+}
+
 func TestMatchAll(t *testing.T) {
 	t.Run("all validators pass", func(t *testing.T) {
 		cmd := NewCommand(
@@ -215,12 +243,9 @@ func TestArgsValidation_Integration(t *testing.T) {
 		}),
 	)
 
-	oldArgs := os.Args
-
 	t.Run("valid args count", func(t *testing.T) {
 		executed = false
-		os.Args = []string{"test", "arg1", "arg2"}
-		defer func() { os.Args = oldArgs }()
+		cmd.SetArgs([]string{"arg1", "arg2"})
 
 		err := cmd.Execute()
 		if err != nil {
@@ -234,8 +259,7 @@ func TestArgsValidation_Integration(t *testing.T) {
 
 	t.Run("invalid args count", func(t *testing.T) {
 		executed = false
-		os.Args = []string{"test", "arg1"}
-		defer func() { os.Args = oldArgs }()
+		cmd.SetArgs([]string{"arg1"})
 
 		err := cmd.Execute()
 		if err == nil {
@@ -2,8 +2,11 @@ package gocli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"time"
 
 	"github.com/gnemade360/go-config/configprovider"
 )
@@ -21,11 +24,63 @@ type Command struct {
 	run     CommandFunc
 	postRun CommandFunc
 
+	persistentPreRun  CommandFunc
+	persistentPostRun CommandFunc
+	hookPolicy        HookPolicy
+
+	finally          CommandFunc
+	postRunOnFailure bool
+
+	onError   ErrorFunc
+	onSuccess CommandFunc
+
+	middleware []Middleware
+
+	panicRecovery  bool
+	crashReportDir string
+	debug          bool
+
+	signalHandling bool
+	signalGrace    time.Duration
+
+	timeout       time.Duration
+	timeoutKey    string
+	activeTimeout *lifecycleTimeout
+
+	positionals   []Arg
+	argValues     map[string]interface{}
 	argValidation ArgsValidator
 	allowedArgs   []string
+	onlyValidArgs bool
+	argCompletion ArgCompletionFunc
+
+	suggestionDistance int
+	disableSuggestions bool
+
+	version           string
+	deprecated        string
+	deprecatedAliases map[string]string
+	removedIn         string
 
 	configProvider configprovider.Provider
+	configKeys     []ConfigKey
+	requiredConfig []string
+	configFile     string
+	configExplain  bool
+	configValues   map[string]interface{}
+
+	flags           []Flag
+	persistentFlags []Flag
+	flagValues      map[string]interface{}
+
+	helpOutput io.Writer
+
+	args   []string
+	in     io.Reader
+	out    io.Writer
+	errOut io.Writer
 
+	inv *Invocation
 	ctx context.Context
 }
 
@@ -33,10 +88,11 @@ type CommandFunc func(cmd *Command, args []string) error
 
 type ArgsValidator func(cmd *Command, args []string) error
 
+type ErrorFunc func(cmd *Command, args []string, err error) error
+
 func NewCommand(opts ...CommandOption) *Command {
 	cmd := &Command{
 		commands: make([]*Command, 0),
-		ctx:      context.Background(),
 	}
 
 	for _, opt := range opts {
@@ -61,14 +117,111 @@ func (c *Command) Execute() error {
 }
 
 func (c *Command) ExecuteContext(ctx context.Context) error {
-	c.ctx = ctx
+	args := c.args
+	if args == nil {
+		args = os.Args[1:]
+	}
+
+	return c.ExecuteInvocation(ctx, &Invocation{Args: args})
+}
+
+func (c *Command) ExecuteInvocation(ctx context.Context, inv *Invocation) (err error) {
+	if inv.depth == 0 && c.debugEnabled() {
+		if err := c.Root().Validate(); err != nil {
+			return err
+		}
+	}
+
+	inv.reset(ctx)
+	bound := c.bind(inv)
+
+	var finishSignals func(error) error
+	defer func() {
+		if finishSignals != nil {
+			err = finishSignals(err)
+		}
+	}()
+
+	args := inv.Args
+	if args == nil {
+		args = []string{}
+	}
+
+	current := bound
+	defer func() {
+		if !current.panicRecoveryEnabled() {
+			return
+		}
+		if r := recover(); r != nil {
+			err = current.recovered(r, args)
+		}
+	}()
+
+	if len(args) > 0 && args[0] == completeCommandName {
+		return bound.complete(bound.OutOrStdout(), args[1:])
+	}
+
+	target, targetArgs, invoked := c.resolve(args)
+	target = target.bindBelow(c, bound, inv)
+	current = target
 
-	args := os.Args[1:]
+	if grace, ok := target.signalGracePeriod(); ok && inv.depth == 0 {
+		inv.ctx, finishSignals = target.handleSignals(inv.ctx, grace)
+	}
+
+	for cmd := target; cmd != nil; cmd = cmd.parent {
+		inv.path = append([]*Command{cmd}, inv.path...)
+	}
+
+	if target == bound && bound.HasSubCommands() && len(targetArgs) > 0 && targetArgs[0] == helpCommandName {
+		helpTarget, err := c.helpTarget(targetArgs[1:])
+		if err != nil {
+			return err
+		}
+		return helpTarget.bindBelow(c, bound, inv).Help()
+	}
+
+	if err := target.checkDeprecations(invoked, target.ErrOrStderr()); err != nil {
+		return err
+	}
+
+	if target.hasHelpFlag(targetArgs) {
+		return target.Help()
+	}
+
+	if target.run == nil && target.HasSubCommands() {
+		if positionals := target.positionalArgs(targetArgs); len(positionals) > 0 {
+			return &UnknownCommandError{
+				Command:     target.CommandPath(),
+				Name:        positionals[0],
+				Suggestions: target.SuggestionsFor(positionals[0]),
+			}
+		}
+	}
 
-	target, targetArgs, err := c.findTarget(args)
+	if err := target.checkFlagConflicts(); err != nil {
+		return err
+	}
+
+	targetArgs, err = target.parseFlags(targetArgs)
 	if err != nil {
 		return err
 	}
+	inv.flagValues = target.flagValues
+
+	if target.explainRequested() {
+		return target.ExplainConfig(target.OutOrStdout())
+	}
+
+	if err := target.parseArgs(targetArgs); err != nil {
+		return err
+	}
+
+	if target.onlyValidArgs {
+		if err := OnlyValidArgs()(target, targetArgs); err != nil {
+			return err
+		}
+	}
 
 	if target.argValidation != nil {
 		if err := target.argValidation(target, targetArgs); err != nil {
@@ -76,66 +229,211 @@ func (c *Command) ExecuteContext(ctx context.Context) error {
 		}
 	}
 
+	if err := target.resolveConfigKeys(); err != nil {
+		return err
+	}
+
+	if err := target.checkRequiredConfig(); err != nil {
+		return err
+	}
+
 	return target.executeLifecycle(targetArgs)
 }
 
 func (c *Command) executeLifecycle(args []string) error {
-	if c.preRun != nil {
-		if err := c.preRun(c, args); err != nil {
-			return fmt.Errorf("preRun failed: %w", err)
-		}
+	if err := c.runLifecycle(args); err != nil {
+		return c.handleError(args, err)
+	}
+
+	return c.handleSuccess(args)
+}
+
+func (c *Command) runLifecycle(args []string) (err error) {
+	timeout, err := c.timeoutFor()
+	if err != nil {
+		return err
+	}
+
+	if timeout > 0 {
+		parent := c.Context()
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		defer cancel()
+		c.ctx = ctx
+		c.activeTimeout = &lifecycleTimeout{timeout: timeout, ctx: ctx, parent: parent}
 	}
 
-	if c.run != nil {
-		if err := c.run(c, args); err != nil {
-			return fmt.Errorf("run failed: %w", err)
+	defer func() {
+		if r := recover(); r != nil {
+			c.runFinalizers(args)
+			panic(r)
+		}
+		if finalizeErr := c.runFinalizers(args); finalizeErr != nil {
+			err = errors.Join(err, finalizeErr)
 		}
+	}()
+
+	return c.applyMiddleware((*Command).runHooks)(c, args)
+}
+
+func (c *Command) runHooks(args []string) error {
+	phases := []struct {
+		name string
+		hook CommandFunc
+	}{
+		{"persistentPreRun", (*Command).runPersistentPreRuns},
+		{"preRun", c.preRun},
+		{"run", c.run},
+		{"postRun", c.postRun},
+		{"persistentPostRun", (*Command).runPersistentPostRuns},
 	}
 
-	if c.postRun != nil {
-		if err := c.postRun(c, args); err != nil {
-			return fmt.Errorf("postRun failed: %w", err)
+	// runErr is only set when run failed on a command that opted into
+	// WithPostRunOnFailure; the post phases still execute in that case.
+	var runErr error
+	errs := make([]error, 0)
+
+	for _, phase := range phases {
+		if phase.hook == nil {
+			continue
 		}
+
+		err := phase.hook(c, args)
+		if timeoutErr := c.timeoutExceeded(phase.name, err); timeoutErr != nil {
+			err = timeoutErr
+		} else if err != nil {
+			err = fmt.Errorf("%s failed: %w", phase.name, err)
+		}
+		if err == nil {
+			continue
+		}
+
+		if phase.name == "run" && c.postRunOnFailure {
+			runErr = err
+			continue
+		}
+		if runErr == nil {
+			return err
+		}
+		errs = append(errs, err)
 	}
 
-	return nil
+	if runErr != nil && len(errs) > 0 {
+		return errors.Join(append([]error{runErr}, errs...)...)
+	}
+	return runErr
 }
 
 func (c *Command) findTarget(args []string) (*Command, []string, error) {
-	if len(args) == 0 {
-		return c, args, nil
+	target, targetArgs, _ := c.resolve(args)
+	return target, targetArgs, nil
+}
+
+// resolve walks args down the command tree and also returns the names that
+// were typed to reach each command below c, so aliases can be told apart.
+func (c *Command) resolve(args []string) (*Command, []string, []string) {
+	flagArgs := make([]string, 0)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" {
+			break
+		}
+
+		// Flags may appear before the subcommand name
+		if isFlagArg(arg) {
+			flagArgs = append(flagArgs, arg)
+			if c.flagNeedsValue(arg) && i+1 < len(args) {
+				i++
+				flagArgs = append(flagArgs, args[i])
+			}
+			continue
+		}
+
+		cmd := c.findCommand(arg)
+		if cmd == nil {
+			break
+		}
+
+		target, targetArgs, invoked := cmd.resolve(args[i+1:])
+		return target, append(flagArgs, targetArgs...), append([]string{arg}, invoked...)
 	}
 
+	return c, args, nil
+}
+
+func (c *Command) findCommand(name string) *Command {
 	for _, cmd := range c.commands {
-		if cmd.commandName == args[0] {
-			return cmd.findTarget(args[1:])
+		if cmd.commandName == name {
+			return cmd
 		}
 
 		// Check aliases
 		for _, alias := range cmd.aliases {
-			if alias == args[0] {
-				return cmd.findTarget(args[1:])
+			if alias == name {
+				return cmd
 			}
 		}
 	}
 
-	return c, args, nil
+	return nil
 }
 
 func (c *Command) Config() configprovider.Provider {
+	provider := c.baseConfig()
+
+	if len(c.visibleFlags()) > 0 {
+		provider = &flagProvider{cmd: c, next: provider}
+	}
+
+	if len(c.configValues) > 0 {
+		provider = &configKeyProvider{values: c.configValues, next: provider}
+	}
+
+	return provider
+}
+
+func (c *Command) baseConfig() configprovider.Provider {
 	if c.configProvider != nil {
 		return c.configProvider
 	}
 
 	if c.parent != nil {
-		return c.parent.Config()
+		return c.parent.baseConfig()
 	}
 
 	return nil
 }
 
+func (c *Command) Root() *Command {
+	if c.parent != nil {
+		return c.parent.Root()
+	}
+	return c
+}
+
 func (c *Command) Context() context.Context {
-	return c.ctx
+	if c.ctx != nil {
+		return c.ctx
+	}
+
+	if c.parent != nil {
+		return c.parent.Context()
+	}
+
+	if c.inv != nil {
+		return c.inv.Context()
+	}
+
+	return context.Background()
+}
+
+func (c *Command) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+func (c *Command) WithContextValue(key, value interface{}) {
+	c.ctx = context.WithValue(c.Context(), key, value)
 }
 
 func (c *Command) Name() string {
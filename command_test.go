@@ -3,8 +3,8 @@ package gocli
 import (
 	"context"
 	"errors"
-	"os"
 	"testing"
+	"time"
 )
 
 func TestNewCommand(t *testing.T) {
@@ -42,9 +42,7 @@ func TestCommand_Execute(t *testing.T) {
 		}),
 	)
 
-	oldArgs := os.Args
-	os.Args = []string{"test"}
-	defer func() { os.Args = oldArgs }()
+	cmd.SetArgs([]string{})
 
 	err := cmd.Execute()
 	if err != nil {
@@ -67,9 +65,7 @@ func TestCommand_ExecuteContext(t *testing.T) {
 		}),
 	)
 
-	oldArgs := os.Args
-	os.Args = []string{"test"}
-	defer func() { os.Args = oldArgs }()
+	cmd.SetArgs([]string{})
 
 	ctx := context.WithValue(context.Background(), "key", "value")
 	err := cmd.ExecuteContext(ctx)
@@ -86,6 +82,114 @@ func TestCommand_ExecuteContext(t *testing.T) {
 	}
 }
 
+func TestCommand_ContextPropagatesToSubcommand(t *testing.T) {
+	type ctxKey string
+
+	var receivedCtx, parentCtx context.Context
+
+	rootCmd := NewCommand(WithName("root"))
+	groupCmd := NewCommand(WithName("group"))
+	leafCmd := NewCommand(
+		WithName("leaf"),
+		WithRun(func(cmd *Command, args []string) error {
+			receivedCtx = cmd.Context()
+			parentCtx = cmd.Parent().Context()
+			return nil
+		}),
+	)
+	groupCmd.AddCommand(leafCmd)
+	rootCmd.AddCommand(groupCmd)
+
+	rootCmd.SetArgs([]string{"group", "leaf"})
+
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), ctxKey("key"), "value"), time.Minute)
+	defer cancel()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		t.Fatalf("ExecuteContext failed: %v", err)
+	}
+
+	if receivedCtx.Value(ctxKey("key")) != "value" {
+		t.Error("subcommand did not receive the context value")
+	}
+
+	if _, ok := receivedCtx.Deadline(); !ok {
+		t.Error("subcommand did not receive the context deadline")
+	}
+
+	if parentCtx != ctx {
+		t.Error("intermediate command did not receive the context")
+	}
+}
+
+func TestCommand_ContextEnrichedInPreRun(t *testing.T) {
+	type ctxKey string
+
+	var user, requestID interface{}
+
+	rootCmd := NewCommand(WithName("root"))
+	subCmd := NewCommand(
+		WithName("sub"),
+		WithPreRun(func(cmd *Command, args []string) error {
+			cmd.Parent().WithContextValue(ctxKey("user"), "alice")
+			cmd.WithContextValue(ctxKey("request"), "42")
+			return nil
+		}),
+		WithRun(func(cmd *Command, args []string) error {
+			user = cmd.Context().Value(ctxKey("user"))
+			requestID = cmd.Context().Value(ctxKey("request"))
+			return nil
+		}),
+	)
+	rootCmd.AddCommand(subCmd)
+
+	rootCmd.SetArgs([]string{"sub"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if user != "alice" || requestID != "42" {
+		t.Errorf("expected enriched context values, got user=%v request=%v", user, requestID)
+	}
+
+	if rootCmd.Context().Value(ctxKey("request")) != nil {
+		t.Error("child context values should not leak to the parent")
+	}
+
+	t.Run("reset on next execution", func(t *testing.T) {
+		subCmd.run = func(cmd *Command, args []string) error {
+			requestID = cmd.Context().Value(ctxKey("request"))
+			return nil
+		}
+		subCmd.preRun = nil
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+
+		if requestID != nil {
+			t.Errorf("expected a fresh context, got request=%v", requestID)
+		}
+	})
+}
+
+func TestCommand_SetContext(t *testing.T) {
+	cmd := NewCommand(WithName("test"))
+
+	if cmd.Context() == nil {
+		t.Fatal("expected a background context before execution")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cmd.SetContext(ctx)
+	if cmd.Context() != ctx {
+		t.Error("SetContext did not replace the context")
+	}
+}
+
 func TestCommand_LifecycleHooks(t *testing.T) {
 	order := []string{}
 
@@ -105,9 +209,7 @@ func TestCommand_LifecycleHooks(t *testing.T) {
 		}),
 	)
 
-	oldArgs := os.Args
-	os.Args = []string{"test"}
-	defer func() { os.Args = oldArgs }()
+	cmd.SetArgs([]string{})
 
 	err := cmd.Execute()
 	if err != nil {
@@ -143,9 +245,7 @@ func TestCommand_LifecycleErrorHandling(t *testing.T) {
 			}),
 		)
 
-		oldArgs := os.Args
-		os.Args = []string{"test"}
-		defer func() { os.Args = oldArgs }()
+		cmd.SetArgs([]string{})
 
 		err := cmd.Execute()
 		if err == nil {
@@ -171,9 +271,7 @@ func TestCommand_LifecycleErrorHandling(t *testing.T) {
 			}),
 		)
 
-		oldArgs := os.Args
-		os.Args = []string{"test"}
-		defer func() { os.Args = oldArgs }()
+		cmd.SetArgs([]string{})
 
 		err := cmd.Execute()
 		if err == nil {
@@ -227,9 +325,7 @@ func TestCommand_SubcommandExecution(t *testing.T) {
 
 	rootCmd.AddCommand(subCmd)
 
-	oldArgs := os.Args
-	os.Args = []string{"root", "sub"}
-	defer func() { os.Args = oldArgs }()
+	rootCmd.SetArgs([]string{"sub"})
 
 	err := rootCmd.Execute()
 	if err != nil {
@@ -300,12 +396,9 @@ func TestCommand_AliasExecution(t *testing.T) {
 
 	rootCmd.AddCommand(versionCmd)
 
-	oldArgs := os.Args
-
 	t.Run("execute with primary name", func(t *testing.T) {
 		executed = false
-		os.Args = []string{"toolbox", "version"}
-		defer func() { os.Args = oldArgs }()
+		rootCmd.SetArgs([]string{"version"})
 
 		err := rootCmd.Execute()
 		if err != nil {
@@ -323,8 +416,7 @@ func TestCommand_AliasExecution(t *testing.T) {
 
 	t.Run("execute with first alias", func(t *testing.T) {
 		executed = false
-		os.Args = []string{"toolbox", "v"}
-		defer func() { os.Args = oldArgs }()
+		rootCmd.SetArgs([]string{"v"})
 
 		err := rootCmd.Execute()
 		if err != nil {
@@ -342,8 +434,7 @@ func TestCommand_AliasExecution(t *testing.T) {
 
 	t.Run("execute with second alias", func(t *testing.T) {
 		executed = false
-		os.Args = []string{"toolbox", "ver"}
-		defer func() { os.Args = oldArgs }()
+		rootCmd.SetArgs([]string{"ver"})
 
 		err := rootCmd.Execute()
 		if err != nil {
@@ -365,3 +456,48 @@ type mockConfigProvider struct{}
 func (m *mockConfigProvider) Read(key string) (interface{}, error) {
 	return nil, nil
 }
+
+// newToolboxTree builds the command tree shared by the help, completion and
+// suggestion tests. Every subcommand sets runExecuted when its run executes.
+func newToolboxTree(runExecuted *bool, opts ...CommandOption) *Command {
+	run := func(cmd *Command, args []string) error {
+		if runExecuted != nil {
+			*runExecuted = true
+		}
+		return nil
+	}
+
+	rootCmd := NewCommand(append([]CommandOption{
+		WithName("toolbox"),
+		WithShort("A CLI toolbox"),
+		WithLong("Toolbox demonstrates help generation."),
+		WithPersistentFlag(BoolFlag("verbose", false, "Enable verbose output")),
+	}, opts...)...)
+
+	rootCmd.AddCommand(
+		NewCommand(
+			WithName("version"),
+			WithAlias("v", "ver"),
+			WithShort("Print version information"),
+			WithRun(run),
+		),
+		NewCommand(
+			WithName("echo"),
+			WithShort("Echo the provided arguments"),
+			WithRun(run),
+		),
+		NewCommand(WithName("uppercase"), WithAlias("upper", "up"), WithShort("Convert input to upper case"), WithRun(run)),
+		NewCommand(WithName("lowercase"), WithAlias("lower", "low"), WithShort("Convert input to lower case"), WithRun(run)),
+		NewCommand(
+			WithName("service"),
+			WithShort("Manage the service"),
+			WithFlag(StringFlag("output", "text", "Output format")),
+			WithAllowedArgs("start", "stop", "restart"),
+			WithRun(run),
+		),
+		NewCommand(WithName("cat"), WithShort("Print files"), WithRun(run)),
+		NewCompletionCommand(),
+	)
+
+	return rootCmd
+}
@@ -0,0 +1,429 @@
+package gocli
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+const (
+	completionCommandName = "completion"
+	completeCommandName   = "__complete"
+)
+
+type Directive int
+
+const (
+	DirectiveDefault       Directive = 0
+	DirectiveNoSpace       Directive = 1
+	DirectiveNoFileComp    Directive = 2
+	DirectiveFilterFileExt Directive = 4
+	DirectiveFilterDirs    Directive = 8
+)
+
+type Completion struct {
+	Value       string
+	Description string
+}
+
+// ArgCompletionFunc returns candidates for the positional argument being
+// completed. With DirectiveFilterFileExt the returned values are the allowed
+// file extensions; with DirectiveFilterDirs an optional single value names
+// the directory to complete from.
+type ArgCompletionFunc func(cmd *Command, args []string, toComplete string) ([]Completion, Directive)
+
+func NewCompletionCommand() *Command {
+	return NewCommand(
+		WithName(completionCommandName),
+		WithShort("Generate shell completion scripts"),
+		WithLong(`Generate a completion script for bash, zsh, fish or powershell.
+
+The generated script calls back into the binary through the hidden
+"__complete" command, so completions stay in sync with the installed
+version without regenerating the script.
+
+  bash:        source <(app completion bash)
+  zsh:         source <(app completion zsh)
+  fish:        app completion fish | source
+  powershell:  app completion powershell | Out-String | Invoke-Expression`),
+		WithAllowedArgs("bash", "zsh", "fish", "powershell"),
+		WithArgValidator(MatchAll(ExactArgs(1), OnlyValidArgs())),
+		WithRun(func(cmd *Command, args []string) error {
+			return cmd.Root().GenCompletion(cmd.OutOrStdout(), args[0])
+		}),
+	)
+}
+
+func (c *Command) GenCompletion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		return c.GenBashCompletion(w)
+	case "zsh":
+		return c.GenZshCompletion(w)
+	case "fish":
+		return c.GenFishCompletion(w)
+	case "powershell":
+		return c.GenPowerShellCompletion(w)
+	default:
+		return &InvalidArgError{Arg: shell, ValidArgs: []string{"bash", "zsh", "fish", "powershell"}}
+	}
+}
+
+func (c *Command) GenBashCompletion(w io.Writer) error {
+	return c.writeCompletionScript(w, bashCompletionTemplate)
+}
+
+func (c *Command) GenZshCompletion(w io.Writer) error {
+	return c.writeCompletionScript(w, zshCompletionTemplate)
+}
+
+func (c *Command) GenFishCompletion(w io.Writer) error {
+	return c.writeCompletionScript(w, fishCompletionTemplate)
+}
+
+func (c *Command) GenPowerShellCompletion(w io.Writer) error {
+	return c.writeCompletionScript(w, powerShellCompletionTemplate)
+}
+
+var nonIdentifierChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+func (c *Command) writeCompletionScript(w io.Writer, tmpl string) error {
+	name := c.Root().commandName
+	script := strings.NewReplacer(
+		"{{name}}", name,
+		"{{fn}}", nonIdentifierChars.ReplaceAllString(name, "_"),
+		"{{complete}}", completeCommandName,
+	).Replace(tmpl)
+
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+func (c *Command) complete(w io.Writer, args []string) error {
+	completions, directive := c.getCompletions(args)
+
+	for _, comp := range completions {
+		if comp.Description != "" {
+			fmt.Fprintf(w, "%s\t%s\n", comp.Value, comp.Description)
+		} else {
+			fmt.Fprintln(w, comp.Value)
+		}
+	}
+
+	_, err := fmt.Fprintf(w, ":%d\n", directive)
+	return err
+}
+
+func (c *Command) getCompletions(args []string) ([]Completion, Directive) {
+	toComplete := ""
+	if len(args) > 0 {
+		toComplete = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	target, targetArgs, err := c.findTarget(args)
+	if err != nil {
+		return nil, DirectiveDefault
+	}
+
+	// The previous word is a flag waiting for its value
+	if n := len(targetArgs); n > 0 && isFlagArg(targetArgs[n-1]) && target.flagNeedsValue(targetArgs[n-1]) {
+		return nil, DirectiveDefault
+	}
+
+	if strings.HasPrefix(toComplete, "-") {
+		return target.flagCompletions(toComplete), DirectiveNoFileComp
+	}
+
+	completions := make([]Completion, 0)
+
+	if len(target.positionalArgs(targetArgs)) == 0 {
+		for _, cmd := range target.visibleCommands() {
+			for _, name := range append([]string{cmd.commandName}, cmd.visibleAliases()...) {
+				if strings.HasPrefix(name, toComplete) {
+					completions = append(completions, Completion{Value: name, Description: cmd.short})
+				}
+			}
+		}
+	}
+
+	for _, arg := range target.allowedArgs {
+		if strings.HasPrefix(arg, toComplete) {
+			completions = append(completions, Completion{Value: arg})
+		}
+	}
+
+	if target.argCompletion != nil {
+		dynamic, directive := target.argCompletion(target, target.positionalArgs(targetArgs), toComplete)
+		if directive&(DirectiveFilterFileExt|DirectiveFilterDirs) != 0 {
+			return dynamic, directive
+		}
+		return append(completions, dynamic...), directive
+	}
+
+	if target.HasSubCommands() || len(target.allowedArgs) > 0 {
+		return completions, DirectiveNoFileComp
+	}
+
+	return completions, DirectiveDefault
+}
+
+func (c *Command) flagCompletions(toComplete string) []Completion {
+	completions := make([]Completion, 0)
+
+	flags := append(c.visibleFlags(), Flag{Name: "help", Usage: "help for " + c.commandName})
+	for _, f := range flags {
+		name := "--" + f.Name
+		if strings.HasPrefix(name, toComplete) {
+			completions = append(completions, Completion{Value: name, Description: f.Usage})
+		}
+	}
+
+	return completions
+}
+
+func (c *Command) positionalArgs(args []string) []string {
+	positionals := make([]string, 0, len(args))
+
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			return append(positionals, args[i+1:]...)
+		}
+		if isFlagArg(args[i]) {
+			if c.flagNeedsValue(args[i]) {
+				i++
+			}
+			continue
+		}
+		positionals = append(positionals, args[i])
+	}
+
+	return positionals
+}
+
+const bashCompletionTemplate = `# bash completion for {{name}}
+
+_{{fn}}_complete() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local directive=0 line
+    local -a candidates=()
+
+    while IFS= read -r line; do
+        if [[ $line == :* ]]; then
+            directive=${line#:}
+        else
+            candidates+=("${line%%$'\t'*}")
+        fi
+    done < <("${COMP_WORDS[0]}" {{complete}} "${COMP_WORDS[@]:1:COMP_CWORD-1}" "$cur" 2>/dev/null)
+
+    COMPREPLY=()
+    local candidate
+
+    if (( directive & 4 )); then
+        compopt -o filenames 2>/dev/null
+        local file ext
+        while IFS= read -r file; do
+            if [[ -d $file ]]; then
+                COMPREPLY+=("$file")
+                continue
+            fi
+            for ext in "${candidates[@]}"; do
+                if [[ $file == *."${ext#.}" ]]; then
+                    COMPREPLY+=("$file")
+                    break
+                fi
+            done
+        done < <(compgen -f -- "$cur")
+        return
+    fi
+
+    if (( directive & 8 )); then
+        compopt -o filenames 2>/dev/null
+        if (( ${#candidates[@]} )); then
+            while IFS= read -r candidate; do
+                COMPREPLY+=("$candidate")
+            done < <(cd "${candidates[0]}" 2>/dev/null && compgen -d -- "$cur")
+        else
+            while IFS= read -r candidate; do
+                COMPREPLY+=("$candidate")
+            done < <(compgen -d -- "$cur")
+        fi
+        return
+    fi
+
+    if (( directive & 1 )); then
+        compopt -o nospace 2>/dev/null
+    fi
+    if (( directive & 2 )); then
+        compopt +o default 2>/dev/null
+    fi
+
+    for candidate in "${candidates[@]}"; do
+        if [[ $candidate == "$cur"* ]]; then
+            COMPREPLY+=("$candidate")
+        fi
+    done
+}
+
+complete -o default -F _{{fn}}_complete {{name}}
+`
+
+const zshCompletionTemplate = `#compdef {{name}}
+
+_{{fn}}() {
+    local directive=0 line value desc
+    local -a completions values nospace
+
+    local out
+    out=$(${words[1]} {{complete}} "${(@)words[2,CURRENT-1]}" "${words[CURRENT]}" 2>/dev/null)
+
+    for line in "${(@f)out}"; do
+        if [[ $line == :* ]]; then
+            directive=${line#:}
+            continue
+        fi
+        value=${line%%$'\t'*}
+        desc=
+        if [[ $line == *$'\t'* ]]; then
+            desc=${line#*$'\t'}
+        fi
+        values+=("$value")
+        completions+=("${value//:/\\:}${desc:+:$desc}")
+    done
+
+    if (( directive & 4 )); then
+        _files -g "*.(${(j:|:)${values#.}})"
+        return
+    fi
+
+    if (( directive & 8 )); then
+        if (( ${#values} )); then
+            _files -/ -W "${values[1]}"
+        else
+            _files -/
+        fi
+        return
+    fi
+
+    if (( directive & 1 )); then
+        nospace=(-S '')
+    fi
+
+    if (( ${#completions} )); then
+        _describe 'completions' completions $nospace && return
+    fi
+
+    if (( ! (directive & 2) )); then
+        _files
+    fi
+}
+
+if [ "$funcstack[1]" = "_{{fn}}" ]; then
+    _{{fn}} "$@"
+else
+    compdef _{{fn}} {{name}}
+fi
+`
+
+const fishCompletionTemplate = `# fish completion for {{name}}
+
+function __{{fn}}_prepare_completions
+    set -g __{{fn}}_comp_results
+    set -l args (commandline -opc)
+    set -e args[1]
+    set -l current (commandline -ct)
+    set -l directive 0
+
+    for line in ({{name}} {{complete}} $args $current 2>/dev/null)
+        if string match -q -- ':*' $line
+            set directive (string sub -s 2 -- $line)
+        else
+            set -a __{{fn}}_comp_results $line
+        end
+    end
+
+    # Offer directories and the files with one of the returned extensions
+    if test (math "bitand($directive, 4)") -ne 0
+        set -l extensions
+        for result in $__{{fn}}_comp_results
+            set -a extensions (string trim -l -c . -- (string replace -r '\t.*' '' -- $result))
+        end
+        set -g __{{fn}}_comp_results
+        for ext in $extensions
+            set -l pattern '(/|\.'(string escape --style=regex -- $ext)')(\t.*)?$'
+            for file in (__fish_complete_suffix .$ext)
+                if string match -q -r -- $pattern $file; and not contains -- $file $__{{fn}}_comp_results
+                    set -a __{{fn}}_comp_results $file
+                end
+            end
+        end
+        return 0
+    end
+
+    if test (math "bitand($directive, 8)") -ne 0
+        set -g __{{fn}}_comp_results (__fish_complete_directories $current)
+        return 0
+    end
+
+    if test (count $__{{fn}}_comp_results) -gt 0
+        return 0
+    end
+
+    # Fall back to file completion unless the command disabled it
+    test (math "bitand($directive, 2)") -ne 0
+end
+
+complete -c {{name}} -e
+complete -c {{name}} -n '__{{fn}}_prepare_completions' -f -a '$__{{fn}}_comp_results'
+`
+
+const powerShellCompletionTemplate = `# powershell completion for {{name}}
+
+Register-ArgumentCompleter -Native -CommandName '{{name}}' -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $program = $commandAst.CommandElements[0].ToString()
+    $elements = @($commandAst.CommandElements | Select-Object -Skip 1 | ForEach-Object { $_.ToString() })
+    if ($wordToComplete -ne '' -and $elements.Count -gt 0) {
+        $elements = @($elements | Select-Object -SkipLast 1)
+    }
+
+    $directive = 0
+    $candidates = @()
+    foreach ($line in (& $program {{complete}} @elements "$wordToComplete" 2>$null)) {
+        if ($line -like ':*') {
+            $directive = [int]$line.Substring(1)
+            continue
+        }
+        $candidates += ,($line -split "` + "`" + `t", 2)
+    }
+
+    if (($directive -band 4) -ne 0) {
+        $extensions = @($candidates | ForEach-Object { '.' + $_[0].TrimStart('.') })
+        Get-ChildItem -Path "$wordToComplete*" -ErrorAction SilentlyContinue |
+            Where-Object { $_.PSIsContainer -or $extensions -contains $_.Extension } |
+            ForEach-Object { [System.Management.Automation.CompletionResult]::new($_.Name, $_.Name, 'ProviderItem', $_.Name) }
+        return
+    }
+
+    if (($directive -band 8) -ne 0) {
+        $base = if ($candidates.Count -gt 0) { Join-Path $candidates[0][0] "$wordToComplete*" } else { "$wordToComplete*" }
+        Get-ChildItem -Path $base -Directory -ErrorAction SilentlyContinue |
+            ForEach-Object { [System.Management.Automation.CompletionResult]::new($_.Name, $_.Name, 'ProviderContainer', $_.Name) }
+        return
+    }
+
+    foreach ($candidate in $candidates) {
+        $value = $candidate[0]
+        if ($value -notlike "$wordToComplete*") {
+            continue
+        }
+        $desc = if ($candidate.Count -gt 1) { $candidate[1] } else { $value }
+        if (($directive -band 1) -eq 0) {
+            $value = "$value "
+        }
+        [System.Management.Automation.CompletionResult]::new($value, $candidate[0], 'ParameterValue', $desc)
+    }
+}
+`
@@ -0,0 +1,197 @@
+package gocli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func completionValues(completions []Completion) []string {
+	values := make([]string, 0, len(completions))
+	for _, comp := range completions {
+		values = append(values, comp.Value)
+	}
+	return values
+}
+
+func TestCommand_GetCompletions(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		wantValues    []string
+		wantDirective Directive
+	}{
+		{"subcommands and aliases", []string{"v"}, []string{"version", "v", "ver"}, DirectiveNoFileComp},
+		{"all subcommands", []string{""}, []string{"version", "v", "ver", "echo", "uppercase", "upper", "up", "lowercase", "lower", "low", "service", "cat", "completion"}, DirectiveNoFileComp},
+		{"allowed args", []string{"service", "st"}, []string{"start", "stop"}, DirectiveNoFileComp},
+		{"allowed args after flag", []string{"service", "--output", "json", "re"}, []string{"restart"}, DirectiveNoFileComp},
+		{"flag names", []string{"service", "--"}, []string{"--output", "--verbose", "--help"}, DirectiveNoFileComp},
+		{"flag value", []string{"service", "--output", ""}, []string{}, DirectiveDefault},
+		{"file fallback", []string{"cat", ""}, []string{}, DirectiveDefault},
+		{"completion shells", []string{"completion", ""}, []string{"bash", "zsh", "fish", "powershell"}, DirectiveNoFileComp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := newToolboxTree(nil)
+
+			completions, directive := rootCmd.getCompletions(tt.args)
+			got := completionValues(completions)
+
+			if strings.Join(got, ",") != strings.Join(tt.wantValues, ",") {
+				t.Errorf("expected completions %v, got %v", tt.wantValues, got)
+			}
+			if directive != tt.wantDirective {
+				t.Errorf("expected directive %d, got %d", tt.wantDirective, directive)
+			}
+		})
+	}
+}
+
+func TestCommand_CompleteOutput(t *testing.T) {
+	rootCmd := newToolboxTree(nil)
+
+	var buf bytes.Buffer
+	if err := rootCmd.complete(&buf, []string{"ver"}); err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+
+	expected := "version\tPrint version information\nver\tPrint version information\n:2\n"
+	if buf.String() != expected {
+		t.Errorf("expected output %q, got %q", expected, buf.String())
+	}
+}
+
+func TestCommand_GenCompletion(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"bash", []string{"complete -o default -F _my_app_complete my-app", "__complete"}},
+		{"zsh", []string{"#compdef my-app", "compdef _my_app my-app", "__complete"}},
+		{"fish", []string{"complete -c my-app", "__my_app_prepare_completions", "__fish_complete_suffix", "__complete"}},
+		{"powershell", []string{"Register-ArgumentCompleter -Native -CommandName 'my-app'", "__complete"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			rootCmd := NewCommand(WithName("my-app"))
+			subCmd := NewCommand(WithName("sub"))
+			rootCmd.AddCommand(subCmd)
+
+			var buf bytes.Buffer
+			if err := subCmd.GenCompletion(&buf, tt.shell); err != nil {
+				t.Fatalf("GenCompletion failed: %v", err)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("expected %s script to contain %q", tt.shell, want)
+				}
+			}
+		})
+	}
+
+	t.Run("unsupported shell", func(t *testing.T) {
+		rootCmd := NewCommand(WithName("my-app"))
+		var buf bytes.Buffer
+		if err := rootCmd.GenCompletion(&buf, "tcsh"); err == nil {
+			t.Error("expected error for unsupported shell, got nil")
+		}
+	})
+}
+
+func TestCompletionCommand_ArgValidation(t *testing.T) {
+	rootCmd := newToolboxTree(nil)
+
+	rootCmd.SetArgs([]string{"completion", "tcsh"})
+
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error for unsupported shell, got nil")
+	}
+}
+
+func TestCommand_ArgCompletion(t *testing.T) {
+	var gotArgs []string
+	var gotToComplete string
+
+	rootCmd := NewCommand(WithName("kube"))
+	getCmd := NewCommand(
+		WithName("get"),
+		WithFlag(StringFlag("namespace", "default", "Namespace")),
+		WithArgCompletion(func(cmd *Command, args []string, toComplete string) ([]Completion, Directive) {
+			gotArgs = args
+			gotToComplete = toComplete
+			return []Completion{
+				{Value: "pods", Description: "Pod resources"},
+				{Value: "services"},
+			}, DirectiveNoFileComp
+		}),
+		WithRun(func(cmd *Command, args []string) error { return nil }),
+	)
+	applyCmd := NewCommand(
+		WithName("apply"),
+		WithArgCompletion(func(cmd *Command, args []string, toComplete string) ([]Completion, Directive) {
+			return []Completion{{Value: "yaml"}, {Value: "json"}}, DirectiveFilterFileExt
+		}),
+	)
+	cdCmd := NewCommand(
+		WithName("cd"),
+		WithAllowedArgs("ignored"),
+		WithArgCompletion(func(cmd *Command, args []string, toComplete string) ([]Completion, Directive) {
+			return nil, DirectiveFilterDirs
+		}),
+	)
+	rootCmd.AddCommand(getCmd, applyCmd, cdCmd)
+
+	t.Run("dynamic candidates", func(t *testing.T) {
+		completions, directive := rootCmd.getCompletions([]string{"get", "--namespace", "kube-system", "first", "p"})
+
+		if got := completionValues(completions); strings.Join(got, ",") != "pods,services" {
+			t.Errorf("expected dynamic completions, got %v", got)
+		}
+		if directive != DirectiveNoFileComp {
+			t.Errorf("expected directive %d, got %d", DirectiveNoFileComp, directive)
+		}
+		if strings.Join(gotArgs, ",") != "first" {
+			t.Errorf("expected positional args [first], got %v", gotArgs)
+		}
+		if gotToComplete != "p" {
+			t.Errorf("expected toComplete 'p', got '%s'", gotToComplete)
+		}
+	})
+
+	t.Run("file extension filter", func(t *testing.T) {
+		completions, directive := rootCmd.getCompletions([]string{"apply", ""})
+
+		if directive != DirectiveFilterFileExt {
+			t.Errorf("expected directive %d, got %d", DirectiveFilterFileExt, directive)
+		}
+		if got := completionValues(completions); strings.Join(got, ",") != "yaml,json" {
+			t.Errorf("expected extensions, got %v", got)
+		}
+	})
+
+	t.Run("directory filter replaces static candidates", func(t *testing.T) {
+		completions, directive := rootCmd.getCompletions([]string{"cd", ""})
+
+		if directive != DirectiveFilterDirs {
+			t.Errorf("expected directive %d, got %d", DirectiveFilterDirs, directive)
+		}
+		if len(completions) != 0 {
+			t.Errorf("expected no candidates, got %v", completionValues(completions))
+		}
+	})
+
+	t.Run("protocol output", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := rootCmd.complete(&buf, []string{"get", ""}); err != nil {
+			t.Fatalf("complete failed: %v", err)
+		}
+
+		expected := "pods\tPod resources\nservices\n:2\n"
+		if buf.String() != expected {
+			t.Errorf("expected output %q, got %q", expected, buf.String())
+		}
+	})
+}
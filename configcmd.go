@@ -0,0 +1,433 @@
+package gocli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gnemade360/go-map-navigator/pkg/mapnavigator"
+	"gopkg.in/yaml.v3"
+)
+
+const configFileName = "config.yaml"
+
+// ConfigFile returns the YAML file the config command writes to: the nearest
+// path set with WithConfigFile, or <user config dir>/<root name>/config.yaml.
+func (c *Command) ConfigFile() (string, error) {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.configFile != "" {
+			return cmd.configFile, nil
+		}
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, c.Root().commandName, configFileName), nil
+}
+
+// NewConfigCommand returns a "config" command with get, set, unset, list and
+// edit subcommands. Values are read through Config() and written to
+// ConfigFile().
+func NewConfigCommand(opts ...CommandOption) *Command {
+	configCmd := NewCommand(append([]CommandOption{
+		WithName("config"),
+		WithShort("Inspect and change the configuration"),
+	}, opts...)...)
+
+	configCmd.AddCommand(
+		NewCommand(
+			WithName("get"),
+			WithShort("Print the value of a config key"),
+			WithArgs(Arg{Name: "key", Required: true}),
+			WithRun(runConfigGet),
+		),
+		NewCommand(
+			WithName("set"),
+			WithShort("Set a config key in the config file"),
+			WithArgs(Arg{Name: "key", Required: true}, Arg{Name: "value", Required: true}),
+			WithRun(runConfigSet),
+		),
+		NewCommand(
+			WithName("unset"),
+			WithShort("Remove a config key from the config file"),
+			WithArgs(Arg{Name: "key", Required: true}),
+			WithRun(runConfigUnset),
+		),
+		NewCommand(
+			WithName("list"),
+			WithShort("List the config file entries and declared config keys"),
+			WithArgValidator(ExactArgs(0)),
+			WithRun(runConfigList),
+		),
+		NewCommand(
+			WithName("edit"),
+			WithShort("Open the config file in $EDITOR"),
+			WithArgValidator(ExactArgs(0)),
+			WithRun(runConfigEdit),
+		),
+	)
+
+	return configCmd
+}
+
+func runConfigGet(cmd *Command, args []string) error {
+	key := cmd.ArgString("key")
+
+	value, ok := readConfigPath(cmd, key)
+	if !ok {
+		return &ConfigKeyError{Key: key, Reason: "not set"}
+	}
+
+	return writeConfigValue(cmd, value)
+}
+
+func runConfigSet(cmd *Command, args []string) error {
+	key := cmd.ArgString("key")
+	value := parseConfigValue(cmd, key, cmd.ArgString("value"))
+
+	return updateConfigFile(cmd, func(path string, values map[string]interface{}) error {
+		return setConfigPath(values, strings.Split(key, "."), value)
+	})
+}
+
+// parseConfigValue returns the value "config set" stores for raw. Declared
+// string keys keep raw as typed. Other values are read as YAML scalars, but only
+// when writing the scalar back gives raw again, so "1.10" is not stored as 1.1.
+func parseConfigValue(cmd *Command, key, raw string) interface{} {
+	for _, declared := range cmd.ConfigKeys() {
+		if declared.Key == key && declared.Type == String {
+			return raw
+		}
+	}
+
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(raw), &value); err != nil || value == nil {
+		return raw
+	}
+
+	data, err := marshalConfig(value)
+	if err != nil || strings.TrimSpace(string(data)) != raw {
+		return raw
+	}
+
+	return value
+}
+
+func runConfigUnset(cmd *Command, args []string) error {
+	key := cmd.ArgString("key")
+
+	return updateConfigFile(cmd, func(path string, values map[string]interface{}) error {
+		if !unsetConfigPath(values, strings.Split(key, ".")) {
+			return &ConfigKeyError{Key: key, Source: "file " + path, Reason: "not set"}
+		}
+		return nil
+	})
+}
+
+func runConfigList(cmd *Command, args []string) error {
+	path, err := cmd.ConfigFile()
+	if err != nil {
+		return err
+	}
+
+	values, err := loadConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	entries := make(map[string]interface{})
+	flattenConfig("", values, entries)
+
+	for _, key := range cmd.ConfigKeys() {
+		if value, ok := readConfigPath(cmd, key.Key); ok {
+			entries[key.Key] = value
+		}
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s = %v\n", key, entries[key])
+	}
+
+	return nil
+}
+
+// runConfigEdit opens a copy of the config file in the editor and only
+// replaces the file when the edited copy parses and its declared keys
+// convert to their types. An invalid copy is kept so no edits are lost.
+func runConfigEdit(cmd *Command, args []string) error {
+	path, err := cmd.ConfigFile()
+	if err != nil {
+		return err
+	}
+
+	original, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "config-*.yaml")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	_, err = tmp.Write(original)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+	}
+
+	editCmd := exec.CommandContext(cmd.Context(), editor[0], append(editor[1:], tmpPath)...)
+	editCmd.Stdin = cmd.InOrStdin()
+	editCmd.Stdout = cmd.OutOrStdout()
+	editCmd.Stderr = cmd.ErrOrStderr()
+	if err := editCmd.Run(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("editor %s: %w", editor[0], err)
+	}
+
+	edited, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(edited, &values); err != nil {
+		return fmt.Errorf("invalid config file, edits kept in %s: %w", tmpPath, err)
+	}
+	if err := cmd.validateConfigFile(path, values); err != nil {
+		return fmt.Errorf("invalid config file, edits kept in %s: %w", tmpPath, err)
+	}
+
+	os.Remove(tmpPath)
+	if bytes.Equal(edited, original) {
+		return nil
+	}
+
+	return writeConfigFile(path, edited)
+}
+
+// readConfigPath reads key through Config(). When no provider knows the full
+// key, the longest known prefix is read and the rest is navigated as a path
+// into the returned map.
+func readConfigPath(cmd *Command, key string) (interface{}, bool) {
+	cfg := cmd.Config()
+	if value, ok := readConfig(cfg, key); ok {
+		return value, true
+	}
+
+	parts := strings.Split(key, ".")
+	for i := len(parts) - 1; i > 0; i-- {
+		parent, ok := readConfig(cfg, strings.Join(parts[:i], "."))
+		if !ok {
+			continue
+		}
+
+		navigator := mapnavigator.NewMapNavigator(nil)
+		navigator.ReadOnly = true
+		if value, err := navigator.VisitNode(parent, parts[i:]...); err == nil && value != nil {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+func writeConfigValue(cmd *Command, value interface{}) error {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		out, err := marshalConfig(value)
+		if err != nil {
+			return err
+		}
+		_, err = cmd.OutOrStdout().Write(out)
+		return err
+	default:
+		_, err := fmt.Fprintln(cmd.OutOrStdout(), value)
+		return err
+	}
+}
+
+func updateConfigFile(cmd *Command, update func(path string, values map[string]interface{}) error) error {
+	path, err := cmd.ConfigFile()
+	if err != nil {
+		return err
+	}
+
+	values, err := loadConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := update(path, values); err != nil {
+		return err
+	}
+
+	if err := cmd.validateConfigFile(path, values); err != nil {
+		return err
+	}
+
+	out, err := marshalConfig(values)
+	if err != nil {
+		return err
+	}
+
+	return writeConfigFile(path, out)
+}
+
+// validateConfigFile checks that every declared config key present in the
+// file converts to its type.
+func (c *Command) validateConfigFile(path string, values map[string]interface{}) error {
+	entries := make(map[string]interface{})
+	flattenConfig("", values, entries)
+
+	for _, key := range c.ConfigKeys() {
+		value, ok := entries[key.Key]
+		if !ok {
+			continue
+		}
+		if _, err := convertConfigValue(key.Type, value); err != nil {
+			return &ConfigKeyError{Key: key.Key, Source: "file " + path, Reason: err.Error()}
+		}
+	}
+
+	return nil
+}
+
+func marshalConfig(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func loadConfigFile(path string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+
+	return values, nil
+}
+
+// writeConfigFile replaces the file through a rename so readers never see a
+// partially written config.
+func writeConfigFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0o600)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+
+	return err
+}
+
+func setConfigPath(values map[string]interface{}, parts []string, value interface{}) error {
+	for i, part := range parts[:len(parts)-1] {
+		next, ok := values[part]
+		if !ok || next == nil {
+			child := make(map[string]interface{})
+			values[part] = child
+			values = child
+			continue
+		}
+
+		child, ok := next.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("config key %q is not a map", strings.Join(parts[:i+1], "."))
+		}
+		values = child
+	}
+
+	values[parts[len(parts)-1]] = value
+	return nil
+}
+
+func unsetConfigPath(values map[string]interface{}, parts []string) bool {
+	if len(parts) == 1 {
+		_, ok := values[parts[0]]
+		delete(values, parts[0])
+		return ok
+	}
+
+	child, ok := values[parts[0]].(map[string]interface{})
+	if !ok || !unsetConfigPath(child, parts[1:]) {
+		return false
+	}
+
+	if len(child) == 0 {
+		delete(values, parts[0])
+	}
+	return true
+}
+
+func flattenConfig(prefix string, values map[string]interface{}, entries map[string]interface{}) {
+	for key, value := range values {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if child, ok := value.(map[string]interface{}); ok && len(child) > 0 {
+			flattenConfig(key, child, entries)
+			continue
+		}
+		entries[key] = value
+	}
+}
@@ -0,0 +1,231 @@
+package gocli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gnemade360/go-config/providers/file"
+)
+
+func runConfigCommand(t *testing.T, path string, args ...string) (string, error) {
+	t.Helper()
+
+	var out bytes.Buffer
+	rootCmd := NewCommand(
+		WithName("app"),
+		WithConfigFile(path),
+		WithConfigProvider(file.New(file.WithFilePath(path))),
+		WithConfigKey(
+			ConfigKey{Key: "database.port", Type: Int, Default: 5432},
+			ConfigKey{Key: "log.level", Default: "info"},
+		),
+	)
+	rootCmd.AddCommand(NewConfigCommand())
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(append([]string{"config"}, args...))
+
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+func TestConfigCommand_SetGetUnset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app", "config.yaml")
+
+	if _, err := runConfigCommand(t, path, "set", "database.host", "db.local"); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+	if _, err := runConfigCommand(t, path, "set", "database.port", "6543"); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("config file was not written: %v", err)
+	}
+	if string(data) != "database:\n  host: db.local\n  port: 6543\n" {
+		t.Errorf("unexpected config file:\n%s", data)
+	}
+
+	if out, err := runConfigCommand(t, path, "get", "database.port"); err != nil || out != "6543\n" {
+		t.Errorf("expected 6543, got %q (%v)", out, err)
+	}
+
+	if out, err := runConfigCommand(t, path, "get", "database"); err != nil || out != "host: db.local\nport: 6543\n" {
+		t.Errorf("expected the database map, got %q (%v)", out, err)
+	}
+
+	if _, err := runConfigCommand(t, path, "unset", "database.host"); err != nil {
+		t.Fatalf("unset failed: %v", err)
+	}
+
+	_, err = runConfigCommand(t, path, "get", "database.host")
+	var keyErr *ConfigKeyError
+	if !errors.As(err, &keyErr) {
+		t.Errorf("expected ConfigKeyError for an unset key, got %v", err)
+	}
+
+	_, err = runConfigCommand(t, path, "unset", "database.host")
+	if !errors.As(err, &keyErr) || keyErr.Error() != `config key "database.host" from file `+path+`: not set` {
+		t.Errorf("expected unset of a missing key to fail, got %v", err)
+	}
+}
+
+func TestConfigCommand_SetValidatesDeclaredKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	_, err := runConfigCommand(t, path, "set", "database.port", "high")
+
+	var keyErr *ConfigKeyError
+	if !errors.As(err, &keyErr) || keyErr.Key != "database.port" {
+		t.Fatalf("expected ConfigKeyError for database.port, got %v", err)
+	}
+
+	if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+		t.Error("config file should not be written for an invalid value")
+	}
+}
+
+func TestConfigCommand_SetKeepsValueText(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	for _, args := range [][]string{
+		{"log.level", "1.10"},
+		{"app.version", "1.10"},
+		{"app.mode", "0755"},
+		{"app.ratio", "0.5"},
+		{"app.debug", "true"},
+	} {
+		if _, err := runConfigCommand(t, path, "set", args[0], args[1]); err != nil {
+			t.Fatalf("set %s failed: %v", args[0], err)
+		}
+	}
+
+	values, err := loadConfigFile(path)
+	if err != nil {
+		t.Fatalf("config file was not written: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"log": map[string]interface{}{"level": "1.10"},
+		"app": map[string]interface{}{"version": "1.10", "mode": "0755", "ratio": 0.5, "debug": true},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+}
+
+func TestConfigCommand_GetNavigatesNestedValues(t *testing.T) {
+	provider := &mapConfigProvider{values: map[string]interface{}{
+		"servers": map[string]interface{}{
+			"primary": map[string]interface{}{"host": "a.example.com"},
+		},
+	}}
+
+	var out bytes.Buffer
+	rootCmd := NewCommand(WithName("app"), WithConfigProvider(provider))
+	rootCmd.AddCommand(NewConfigCommand())
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"config", "get", "servers.primary.host"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if out.String() != "a.example.com\n" {
+		t.Errorf("expected a.example.com, got %q", out.String())
+	}
+}
+
+func TestConfigCommand_List(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("database:\n  port: 6543\nfeatures: [a, b]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runConfigCommand(t, path, "list")
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+
+	want := "database.port = 6543\nfeatures = [a b]\nlog.level = info\n"
+	if out != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, out)
+	}
+}
+
+func TestConfigCommand_Edit(t *testing.T) {
+	writeEditor := func(t *testing.T, content string) {
+		t.Helper()
+		script := filepath.Join(t.TempDir(), "editor.sh")
+		body := "#!/bin/sh\ncat > \"$1\" <<'EOF'\n" + content + "EOF\n"
+		if err := os.WriteFile(script, []byte(body), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("EDITOR", script)
+	}
+
+	t.Run("valid edit replaces the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		writeEditor(t, "log:\n  level: debug\n")
+
+		if _, err := runConfigCommand(t, path, "edit"); err != nil {
+			t.Fatalf("edit failed: %v", err)
+		}
+
+		data, _ := os.ReadFile(path)
+		if string(data) != "log:\n  level: debug\n" {
+			t.Errorf("expected edited content, got:\n%s", data)
+		}
+	})
+
+	for name, content := range map[string]string{
+		"invalid yaml": "log: [debug\n",
+		"invalid type": "database:\n  port: high\n",
+	} {
+		t.Run(name+" keeps the file", func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte("log:\n  level: info\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			writeEditor(t, content)
+			t.Setenv("TMPDIR", t.TempDir())
+
+			_, err := runConfigCommand(t, path, "edit")
+			if err == nil || !strings.Contains(err.Error(), "invalid config file, edits kept in ") {
+				t.Fatalf("expected an invalid config file error, got %v", err)
+			}
+
+			data, _ := os.ReadFile(path)
+			if string(data) != "log:\n  level: info\n" {
+				t.Errorf("config file should be unchanged, got:\n%s", data)
+			}
+
+			if kept, _ := filepath.Glob(filepath.Join(os.Getenv("TMPDIR"), "config-*.yaml")); len(kept) != 1 {
+				t.Errorf("expected the edited copy to be kept, found %v", kept)
+			}
+		})
+	}
+}
+
+func TestCommand_ConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	rootCmd := NewCommand(WithName("toolbox"))
+	configCmd := NewConfigCommand()
+	rootCmd.AddCommand(configCmd)
+
+	path, err := configCmd.ConfigFile()
+	if err != nil {
+		t.Fatalf("ConfigFile failed: %v", err)
+	}
+
+	if path != filepath.Join(dir, "toolbox", "config.yaml") {
+		t.Errorf("unexpected config file path %q", path)
+	}
+}
@@ -0,0 +1,333 @@
+package gocli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gnemade360/go-config/configprovider"
+	configerrors "github.com/gnemade360/go-config/errors"
+	"github.com/gnemade360/go-config/providers/env"
+	"github.com/gnemade360/go-config/providers/file"
+	"github.com/gnemade360/go-config/providers/sequential"
+)
+
+const explainConfigFlag = "explain-config"
+
+type ConfigKey struct {
+	Key         string
+	Type        ValueType
+	Default     interface{}
+	Description string
+	Env         string
+	Required    bool
+}
+
+type configSourceKind int
+
+const (
+	sourceFlag configSourceKind = iota
+	sourceEnv
+	sourceFile
+	sourceProvider
+	sourceDefault
+)
+
+type configSource struct {
+	kind     configSourceKind
+	location string
+	value    interface{}
+	found    bool
+}
+
+func (s configSource) String() string {
+	switch s.kind {
+	case sourceFlag:
+		return "flag --" + s.location
+	case sourceEnv:
+		return "env " + s.location
+	case sourceFile:
+		return "file " + s.location
+	case sourceProvider:
+		return "provider " + s.location
+	default:
+		return "default"
+	}
+}
+
+type configKeyProvider struct {
+	values map[string]interface{}
+	next   configprovider.Provider
+}
+
+func (p *configKeyProvider) Read(key string) (interface{}, error) {
+	if value, ok := p.values[key]; ok {
+		return value, nil
+	}
+
+	if p.next == nil {
+		return nil, &configerrors.ConfigNotFoundError{Key: key}
+	}
+	return p.next.Read(key)
+}
+
+// ConfigKeys returns the keys declared on the command and its ancestors. A key
+// declared closer to the command replaces an ancestor's declaration.
+func (c *Command) ConfigKeys() []ConfigKey {
+	var keys []ConfigKey
+	if c.parent != nil {
+		keys = c.parent.ConfigKeys()
+	}
+
+	for _, key := range c.configKeys {
+		replaced := false
+		for i := range keys {
+			if keys[i].Key == key.Key {
+				keys[i] = key
+				replaced = true
+			}
+		}
+		if !replaced {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func (c *Command) validateConfigKeys(report func(format string, args ...interface{})) {
+	seen := make(map[string]bool)
+
+	for i, key := range c.configKeys {
+		if key.Key == "" {
+			report("%q: config key %d has an empty name", c.CommandPath(), i)
+			continue
+		}
+		if seen[key.Key] {
+			report("%q: config key %q is declared more than once", c.CommandPath(), key.Key)
+		}
+		seen[key.Key] = true
+
+		if key.Default != nil {
+			if _, err := convertConfigValue(key.Type, key.Default); err != nil {
+				report("%q: default of config key %q: %v", c.CommandPath(), key.Key, err)
+			}
+		}
+	}
+}
+
+func (c *Command) resolveConfigKeys() error {
+	keys := c.ConfigKeys()
+	if len(keys) == 0 {
+		return nil
+	}
+
+	values := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		value, ok, err := c.resolveConfigKey(key)
+		if err != nil {
+			return err
+		}
+		if ok {
+			values[key.Key] = value
+		}
+	}
+
+	c.configValues = values
+	return nil
+}
+
+func (c *Command) resolveConfigKey(key ConfigKey) (interface{}, bool, error) {
+	for _, source := range c.configSources(key) {
+		if !source.found {
+			continue
+		}
+
+		value, err := convertConfigValue(key.Type, source.value)
+		if err != nil {
+			return nil, false, &ConfigKeyError{Key: key.Key, Source: source.String(), Reason: err.Error()}
+		}
+		return value, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// configSources lists every place a key can come from, in precedence order:
+// a flag of the same name, the key's env var, each provider of the config
+// chain and finally the default.
+func (c *Command) configSources(key ConfigKey) []configSource {
+	sources := make([]configSource, 0)
+
+	if _, ok := c.lookupFlag(key.Key); ok {
+		value, found := c.flagValues[key.Key]
+		sources = append(sources, configSource{kind: sourceFlag, location: key.Key, value: value, found: found})
+	}
+
+	if key.Env != "" {
+		value, found := os.LookupEnv(key.Env)
+		sources = append(sources, configSource{kind: sourceEnv, location: key.Env, value: value, found: found})
+	}
+
+	if provider := c.baseConfig(); provider != nil {
+		sources = append(sources, providerSources(provider, key.Key)...)
+	}
+
+	if key.Default != nil {
+		sources = append(sources, configSource{kind: sourceDefault, value: key.Default, found: true})
+	}
+
+	return sources
+}
+
+func providerSources(provider configprovider.Provider, key string) []configSource {
+	if chain, ok := provider.(*sequential.Provider); ok {
+		sources := make([]configSource, 0, len(chain.ConfigProviders))
+		for _, info := range chain.ConfigProviders {
+			if info.Provider == nil {
+				continue
+			}
+			k := key
+			if info.Path != "" {
+				k = info.Path + "." + key
+			}
+			sources = append(sources, providerSources(info.Provider, k)...)
+		}
+		return sources
+	}
+
+	source := describeProvider(provider, key)
+	value, err := provider.Read(key)
+	source.value, source.found = value, err == nil && value != nil
+	return []configSource{source}
+}
+
+func describeProvider(provider configprovider.Provider, key string) configSource {
+	switch p := provider.(type) {
+	case *env.Provider:
+		if p.Transform != nil {
+			key = p.Transform(key)
+		}
+		return configSource{kind: sourceEnv, location: p.Prefix + key}
+	case *file.Provider:
+		return configSource{kind: sourceFile, location: p.FilePath}
+	default:
+		return configSource{kind: sourceProvider, location: fmt.Sprintf("%T", provider)}
+	}
+}
+
+// ExplainConfig writes, for every declared config key, each source that was
+// consulted, the value it holds and whether it was used or shadowed.
+func (c *Command) ExplainConfig(w io.Writer) error {
+	var b strings.Builder
+
+	for i, key := range c.ConfigKeys() {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "%s (%s)", key.Key, key.Type)
+		if key.Description != "" {
+			fmt.Fprintf(&b, ": %s", key.Description)
+		}
+		b.WriteString("\n")
+
+		sources := c.configSources(key)
+		width := 0
+		for _, source := range sources {
+			width = max(width, len(source.String()))
+		}
+
+		used := false
+		for _, source := range sources {
+			switch {
+			case !source.found:
+				fmt.Fprintf(&b, "    %-*s  not set\n", width, source.String())
+			case !used:
+				used = true
+				fmt.Fprintf(&b, "  * %-*s  %v\n", width, source.String(), source.value)
+			default:
+				fmt.Fprintf(&b, "    %-*s  %v (shadowed)\n", width, source.String(), source.value)
+			}
+		}
+
+		if !used {
+			if key.Required {
+				b.WriteString("  required key is not set\n")
+			} else {
+				b.WriteString("  not set\n")
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (c *Command) explainEnabled() bool {
+	if c.configExplain {
+		return true
+	}
+
+	if c.parent != nil {
+		return c.parent.explainEnabled()
+	}
+
+	return false
+}
+
+func (c *Command) explainRequested() bool {
+	return c.explainEnabled() && c.flagValues[explainConfigFlag] == true
+}
+
+func convertConfigValue(t ValueType, value interface{}) (interface{}, error) {
+	if s, ok := value.(string); ok && t != String {
+		parsed, err := t.parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q", t, s)
+		}
+		return parsed, nil
+	}
+
+	switch t {
+	case String:
+		if _, ok := value.(string); ok {
+			return value, nil
+		}
+		return fmt.Sprint(value), nil
+	case Int:
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case int64:
+			return int(v), nil
+		case float64:
+			if v == float64(int(v)) {
+				return int(v), nil
+			}
+		}
+	case Bool:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case Duration:
+		if v, ok := value.(time.Duration); ok {
+			return v, nil
+		}
+	case StringSlice:
+		switch v := value.(type) {
+		case []string:
+			return v, nil
+		case []interface{}:
+			strs := make([]string, 0, len(v))
+			for _, item := range v {
+				strs = append(strs, fmt.Sprint(item))
+			}
+			return strs, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid %s value %v", t, value)
+}
@@ -0,0 +1,222 @@
+package gocli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gnemade360/go-config/configutil"
+	"github.com/gnemade360/go-config/providers/env"
+	"github.com/gnemade360/go-config/providers/sequential"
+)
+
+func TestCommand_ConfigKeyPrecedence(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		provider map[string]interface{}
+		want     string
+	}{
+		{"default", nil, nil, nil, "localhost"},
+		{"provider", nil, nil, map[string]interface{}{"database.host": "file.example.com"}, "file.example.com"},
+		{"chain env", nil, map[string]string{"APP_DATABASE_HOST": "chain.example.com"}, map[string]interface{}{"database.host": "file.example.com"}, "chain.example.com"},
+		{"key env", nil, map[string]string{"DB_HOST": "env.example.com", "APP_DATABASE_HOST": "chain.example.com"}, nil, "env.example.com"},
+		{"flag", []string{"--database.host", "flag.example.com"}, map[string]string{"DB_HOST": "env.example.com"}, nil, "flag.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			var host string
+			rootCmd := NewCommand(
+				WithName("app"),
+				WithConfigProvider(sequential.New(sequential.WithProviders(
+					&env.Provider{Prefix: "APP_", Transform: env.ToUpper},
+					&mapConfigProvider{values: tt.provider},
+				))),
+				WithConfigKey(ConfigKey{Key: "database.host", Default: "localhost", Env: "DB_HOST"}),
+			)
+			rootCmd.AddCommand(NewCommand(
+				WithName("connect"),
+				WithFlag(StringFlag("database.host", "", "Database host")),
+				WithRun(func(cmd *Command, args []string) error {
+					host = configutil.GetString(cmd.Config(), "database.host", "")
+					return nil
+				}),
+			))
+
+			rootCmd.SetArgs(append([]string{"connect"}, tt.args...))
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			if host != tt.want {
+				t.Errorf("expected host %q, got %q", tt.want, host)
+			}
+		})
+	}
+}
+
+func TestCommand_ConfigKeyTypes(t *testing.T) {
+	t.Setenv("APP_DATABASE_TIMEOUT", "5s")
+
+	var port interface{}
+	var timeout time.Duration
+
+	provider := &mapConfigProvider{values: map[string]interface{}{"database.port": float64(6543)}}
+	rootCmd := NewCommand(
+		WithName("app"),
+		WithConfigProvider(sequential.New(sequential.WithProviders(
+			&env.Provider{Prefix: "APP_", Transform: env.ToUpper},
+			provider,
+		))),
+	)
+	rootCmd.AddCommand(NewCommand(
+		WithName("connect"),
+		WithConfigKey(
+			ConfigKey{Key: "database.port", Type: Int, Default: 5432},
+			ConfigKey{Key: "database.timeout", Type: Duration},
+		),
+		WithRun(func(cmd *Command, args []string) error {
+			port, _ = cmd.Config().Read("database.port")
+			timeout = configutil.GetDuration(cmd.Config(), "database.timeout", 0)
+			return nil
+		}),
+	))
+
+	rootCmd.SetArgs([]string{"connect"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if port != 6543 {
+		t.Errorf("expected port converted to int 6543, got %#v", port)
+	}
+
+	if timeout != 5*time.Second {
+		t.Errorf("expected timeout 5s, got %v", timeout)
+	}
+}
+
+func TestCommand_ConfigKeyErrors(t *testing.T) {
+	t.Run("invalid value", func(t *testing.T) {
+		t.Setenv("APP_DATABASE_PORT", "high")
+
+		rootCmd := NewCommand(
+			WithName("app"),
+			WithConfigProvider(&env.Provider{Prefix: "APP_", Transform: env.ToUpper}),
+		)
+		rootCmd.AddCommand(NewCommand(
+			WithName("connect"),
+			WithConfigKey(ConfigKey{Key: "database.port", Type: Int, Default: 5432}),
+			WithRun(func(cmd *Command, args []string) error {
+				t.Error("run should not execute with an invalid config value")
+				return nil
+			}),
+		))
+
+		rootCmd.SetArgs([]string{"connect"})
+		err := rootCmd.Execute()
+
+		var keyErr *ConfigKeyError
+		if !errors.As(err, &keyErr) {
+			t.Fatalf("expected ConfigKeyError, got %v", err)
+		}
+
+		want := `config key "database.port" from env APP_DATABASE_PORT: invalid int value "high"`
+		if err.Error() != want {
+			t.Errorf("expected %q, got %q", want, err.Error())
+		}
+
+		if ExitCode(err) != ExitConfig {
+			t.Errorf("expected exit code %d, got %d", ExitConfig, ExitCode(err))
+		}
+	})
+}
+
+func TestCommand_ExplainConfig(t *testing.T) {
+	t.Setenv("APP_DATABASE_HOST", "chain.example.com")
+
+	provider := &mapConfigProvider{values: map[string]interface{}{"database.host": "file.example.com"}}
+	rootCmd := NewCommand(
+		WithName("app"),
+		WithConfigProvider(sequential.New(sequential.WithProviders(
+			&env.Provider{Prefix: "APP_", Transform: env.ToUpper},
+			provider,
+		))),
+		WithConfigExplain(),
+		WithConfigKey(ConfigKey{Key: "database.host", Default: "localhost", Description: "Database host", Env: "DB_HOST"}),
+	)
+	rootCmd.AddCommand(NewCommand(
+		WithName("connect"),
+		WithFlag(StringFlag("database.host", "", "Database host")),
+		WithConfigKey(
+			ConfigKey{Key: "database.port", Type: Int, Default: 5432},
+			ConfigKey{Key: "database.timeout", Type: Duration},
+		),
+		WithRun(func(cmd *Command, args []string) error {
+			t.Error("run should not execute when explaining config")
+			return nil
+		}),
+	))
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"connect", "--explain-config"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	want := []string{
+		"database.host (string): Database host",
+		"    flag --database.host               not set",
+		"    env DB_HOST                        not set",
+		"  * env APP_DATABASE_HOST              chain.example.com",
+		"    provider *gocli.mapConfigProvider  file.example.com (shadowed)",
+		"    default                            localhost (shadowed)",
+		"database.port (int)",
+		"  * default                            5432",
+		"database.timeout (duration)",
+		"  not set",
+	}
+
+	for _, line := range want {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("expected explain output to contain %q, got:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestCommand_ValidateConfigKeys(t *testing.T) {
+	cmd := NewCommand(
+		WithName("app"),
+		WithConfigKey(
+			ConfigKey{Key: "port", Type: Int, Default: "http"},
+			ConfigKey{Key: "port", Type: Int},
+			ConfigKey{},
+		),
+	)
+
+	err := cmd.Validate()
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+
+	want := []string{
+		`"app": default of config key "port": invalid int value "http"`,
+		`"app": config key "port" is declared more than once`,
+		`"app": config key 2 has an empty name`,
+	}
+	if strings.Join(validationErr.Problems, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected problems %q, got %q", want, validationErr.Problems)
+	}
+}
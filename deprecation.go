@@ -0,0 +1,147 @@
+package gocli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+func (c *Command) Version() string {
+	if c.version != "" {
+		return c.version
+	}
+
+	if c.parent != nil {
+		return c.parent.Version()
+	}
+
+	return ""
+}
+
+func (c *Command) Deprecated() string {
+	return c.deprecated
+}
+
+func (c *Command) IsDeprecated() bool {
+	return c.deprecated != ""
+}
+
+func (c *Command) IsAliasDeprecated(alias string) bool {
+	_, ok := c.deprecatedAliases[alias]
+	return ok
+}
+
+// checkDeprecations inspects every command on the path to c, using the names
+// that were typed to reach them, and either warns once per deprecation or
+// fails when the application version has reached the removal version.
+func (c *Command) checkDeprecations(invoked []string, w io.Writer) error {
+	path := make([]*Command, 0, len(invoked))
+	for cmd := c; cmd != nil && len(path) < len(invoked); cmd = cmd.parent {
+		path = append([]*Command{cmd}, path...)
+	}
+
+	warned := make(map[string]bool)
+	for i, cmd := range path {
+		name := invoked[i]
+
+		if cmd.deprecated != "" {
+			if err := cmd.deprecation(name, cmd.deprecated, false); err != nil {
+				return err
+			}
+			warning := fmt.Sprintf("Command %q is deprecated, %s", cmd.commandName, cmd.deprecated)
+			if !warned[warning] {
+				warned[warning] = true
+				fmt.Fprintln(w, warning)
+			}
+		}
+
+		if message, ok := cmd.deprecatedAliases[name]; ok {
+			if err := cmd.deprecation(name, message, true); err != nil {
+				return err
+			}
+			warning := fmt.Sprintf("Alias %q for %q is deprecated, %s", name, cmd.commandName, message)
+			if !warned[warning] {
+				warned[warning] = true
+				fmt.Fprintln(w, warning)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Command) deprecation(name, message string, alias bool) error {
+	version := c.Version()
+	if c.removedIn == "" || version == "" || compareVersions(version, c.removedIn) < 0 {
+		return nil
+	}
+
+	return &DeprecatedError{
+		Name:      name,
+		Alias:     alias,
+		Message:   message,
+		RemovedIn: c.removedIn,
+		Version:   version,
+	}
+}
+
+func (c *Command) visibleAliases() []string {
+	aliases := make([]string, 0, len(c.aliases))
+	for _, alias := range c.aliases {
+		if !c.IsAliasDeprecated(alias) {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+func (c *Command) visibleCommands() []*Command {
+	commands := make([]*Command, 0, len(c.commands))
+	for _, cmd := range c.commands {
+		if !cmd.IsDeprecated() {
+			commands = append(commands, cmd)
+		}
+	}
+	return commands
+}
+
+func compareVersions(a, b string) int {
+	as, bs := versionParts(a), versionParts(b)
+
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+
+		xn, xErr := strconv.Atoi(x)
+		yn, yErr := strconv.Atoi(y)
+		if xErr != nil || yErr != nil {
+			if cmp := strings.Compare(x, y); cmp != 0 {
+				return cmp
+			}
+			continue
+		}
+
+		if xn < yn {
+			return -1
+		}
+		if xn > yn {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func versionParts(version string) []string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if idx := strings.IndexAny(version, "-+"); idx >= 0 {
+		version = version[:idx]
+	}
+	return strings.Split(version, ".")
+}
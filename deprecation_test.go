@@ -0,0 +1,245 @@
+package gocli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"v1.2.0", "1.2", 0},
+		{"1.2.0", "1.10.0", -1},
+		{"2.0.0", "1.9.9", 1},
+		{"1.5.0-rc.1", "1.5.0", 0},
+		{"1.4", "1.5.0", -1},
+	}
+
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCommand_CheckDeprecations(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		warnings []string
+	}{
+		{"deprecated command", []string{"old"}, []string{`Command "old" is deprecated, use "new" instead`}},
+		{"deprecated alias", []string{"ver"}, []string{`Alias "ver" for "version" is deprecated, use "version" instead`}},
+		{"primary name", []string{"version"}, nil},
+		{"active alias", []string{"v"}, nil},
+	}
+
+	rootCmd := NewCommand(WithName("toolbox"))
+	rootCmd.AddCommand(
+		NewCommand(
+			WithName("version"),
+			WithAlias("v"),
+			WithDeprecatedAlias("ver", `use "version" instead`),
+		),
+		NewCommand(WithName("old"), WithDeprecated(`use "new" instead`)),
+	)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, _, invoked := rootCmd.resolve(tt.args)
+
+			var buf bytes.Buffer
+			if err := target.checkDeprecations(invoked, &buf); err != nil {
+				t.Fatalf("checkDeprecations failed: %v", err)
+			}
+
+			got := strings.TrimSpace(buf.String())
+			if got != strings.Join(tt.warnings, "\n") {
+				t.Errorf("expected warnings %q, got %q", tt.warnings, got)
+			}
+		})
+	}
+}
+
+func TestCommand_DeprecationWarnsForEachCommandOnPath(t *testing.T) {
+	rootCmd := NewCommand(WithName("app"))
+	groupCmd := NewCommand(WithName("legacy"), WithDeprecated("it will be removed"))
+	leafCmd := NewCommand(WithName("run"), WithDeprecated("it will be removed"))
+	groupCmd.AddCommand(leafCmd)
+	rootCmd.AddCommand(groupCmd)
+
+	target, _, invoked := rootCmd.resolve([]string{"legacy", "run"})
+
+	var buf bytes.Buffer
+	if err := target.checkDeprecations(invoked, &buf); err != nil {
+		t.Fatalf("checkDeprecations failed: %v", err)
+	}
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+		t.Errorf("expected one warning per deprecated command, got:\n%s", buf.String())
+	}
+}
+
+func TestCommand_DeprecatedStillExecutes(t *testing.T) {
+	var executed string
+
+	rootCmd := NewCommand(WithName("toolbox"))
+	rootCmd.AddCommand(NewCommand(
+		WithName("version"),
+		WithDeprecatedAlias("ver", `use "version" instead`),
+		WithRun(func(cmd *Command, args []string) error {
+			executed = cmd.Name()
+			return nil
+		}),
+	))
+
+	var errBuf bytes.Buffer
+	rootCmd.SetArgs([]string{"ver"})
+	rootCmd.SetErr(&errBuf)
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if executed != "version" {
+		t.Errorf("expected version to execute, got '%s'", executed)
+	}
+
+	if !strings.Contains(errBuf.String(), `Alias "ver" for "version" is deprecated`) {
+		t.Errorf("expected deprecation warning on stderr, got %q", errBuf.String())
+	}
+}
+
+func TestCommand_DeprecationRemovedInVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		args    []string
+		wantErr bool
+	}{
+		{"command before removal", "1.4.9", []string{"old"}, false},
+		{"command at removal", "1.5.0", []string{"old"}, true},
+		{"alias before removal", "1.9.0", []string{"ver"}, false},
+		{"alias after removal", "2.1.0", []string{"ver"}, true},
+		{"primary name after removal", "2.1.0", []string{"version"}, false},
+		{"no version configured", "", []string{"old"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var executed string
+			run := func(cmd *Command, args []string) error {
+				executed = cmd.Name()
+				return nil
+			}
+
+			rootCmd := NewCommand(WithName("toolbox"), WithVersion(tt.version))
+			rootCmd.AddCommand(
+				NewCommand(
+					WithName("version"),
+					WithDeprecatedAlias("ver", `use "version" instead`),
+					WithRemovedIn("2.0.0"),
+					WithRun(run),
+				),
+				NewCommand(
+					WithName("old"),
+					WithDeprecated(`use "new" instead`),
+					WithRemovedIn("v1.5"),
+					WithRun(run),
+				),
+			)
+
+			rootCmd.SetArgs(tt.args)
+			rootCmd.SetErr(&bytes.Buffer{})
+
+			err := rootCmd.Execute()
+
+			var deprecatedErr *DeprecatedError
+			if tt.wantErr {
+				if !errors.As(err, &deprecatedErr) {
+					t.Fatalf("expected DeprecatedError, got %v", err)
+				}
+				if executed != "" {
+					t.Error("run should not execute for a removed command")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestCommand_DeprecatedHiddenFromHelpAndCompletion(t *testing.T) {
+	rootCmd := NewCommand(WithName("toolbox"))
+	rootCmd.AddCommand(
+		NewCommand(
+			WithName("version"),
+			WithAlias("v"),
+			WithDeprecatedAlias("ver", `use "version" instead`),
+			WithShort("Print version information"),
+		),
+		NewCommand(WithName("old"), WithShort("Legacy command"), WithDeprecated(`use "new" instead`)),
+	)
+
+	help := rootCmd.HelpString()
+	if strings.Contains(help, "old") {
+		t.Errorf("deprecated command should be hidden from help, got:\n%s", help)
+	}
+
+	versionHelp := rootCmd.commands[0].HelpString()
+	if !strings.Contains(versionHelp, "Aliases:\n  version, v\n") {
+		t.Errorf("deprecated alias should be hidden from help, got:\n%s", versionHelp)
+	}
+
+	completions, _ := rootCmd.getCompletions([]string{""})
+	for _, value := range completionValues(completions) {
+		if value == "old" || value == "ver" {
+			t.Errorf("deprecated item %q should be hidden from completion", value)
+		}
+	}
+
+	if got := rootCmd.SuggestionsFor("odl"); len(got) != 0 {
+		t.Errorf("deprecated command should not be suggested, got %v", got)
+	}
+}
+
+func TestCommand_DeprecatedAliasBeforeAlias(t *testing.T) {
+	var errOut bytes.Buffer
+	var executed string
+
+	rootCmd := NewCommand(WithName("toolbox"))
+	rootCmd.AddCommand(NewCommand(
+		WithName("version"),
+		WithDeprecatedAlias("ver", `use "version" instead`),
+		WithAlias("v"),
+		WithRun(func(cmd *Command, args []string) error {
+			executed = cmd.Name()
+			return nil
+		}),
+	))
+	rootCmd.SetErr(&errOut)
+
+	for _, name := range []string{"v", "ver"} {
+		executed = ""
+		rootCmd.SetArgs([]string{name})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Execute %q failed: %v", name, err)
+		}
+		if executed != "version" {
+			t.Errorf("expected %q to run version, got %q", name, executed)
+		}
+	}
+
+	if !strings.Contains(errOut.String(), `Alias "ver" for "version" is deprecated`) {
+		t.Errorf("expected deprecation warning for ver, got %q", errOut.String())
+	}
+}
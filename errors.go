@@ -2,7 +2,10 @@ package gocli
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"syscall"
+	"time"
 )
 
 type InvalidArgsError struct {
@@ -14,6 +17,10 @@ func (e *InvalidArgsError) Error() string {
 	return fmt.Sprintf("invalid number of arguments: expected %s, received %d", e.Expected, e.Received)
 }
 
+func (e *InvalidArgsError) ExitCode() int {
+	return ExitUsage
+}
+
 type InvalidArgError struct {
 	Arg       string
 	ValidArgs []string
@@ -25,3 +32,239 @@ func (e *InvalidArgError) Error() string {
 	}
 	return fmt.Sprintf("invalid argument %q, valid arguments are: %s", e.Arg, strings.Join(e.ValidArgs, ", "))
 }
+
+func (e *InvalidArgError) ExitCode() int {
+	return ExitUsage
+}
+
+type UnknownFlagError struct {
+	Flag string
+}
+
+func (e *UnknownFlagError) Error() string {
+	return fmt.Sprintf("unknown flag: %s", e.Flag)
+}
+
+func (e *UnknownFlagError) ExitCode() int {
+	return ExitUsage
+}
+
+type MissingFlagValueError struct {
+	Flag string
+}
+
+func (e *MissingFlagValueError) Error() string {
+	return fmt.Sprintf("flag needs an argument: %s", e.Flag)
+}
+
+func (e *MissingFlagValueError) ExitCode() int {
+	return ExitUsage
+}
+
+type InvalidFlagValueError struct {
+	Flag     string
+	Value    string
+	Expected string
+}
+
+func (e *InvalidFlagValueError) Error() string {
+	return fmt.Sprintf("invalid value %q for flag %s: expected %s", e.Value, e.Flag, e.Expected)
+}
+
+func (e *InvalidFlagValueError) ExitCode() int {
+	return ExitUsage
+}
+
+type FlagConflictError struct {
+	Flag     string
+	Command  string
+	Ancestor string
+}
+
+func (e *FlagConflictError) Error() string {
+	return fmt.Sprintf("flag --%s on %q conflicts with persistent flag inherited from %q", e.Flag, e.Command, e.Ancestor)
+}
+
+type UnknownCommandError struct {
+	Command     string
+	Name        string
+	Suggestions []string
+}
+
+func (e *UnknownCommandError) Error() string {
+	msg := fmt.Sprintf("unknown command %q for %q", e.Name, e.Command)
+	if len(e.Suggestions) == 0 {
+		return msg
+	}
+	return fmt.Sprintf("%s\n\nDid you mean this?\n\t%s", msg, strings.Join(e.Suggestions, "\n\t"))
+}
+
+func (e *UnknownCommandError) ExitCode() int {
+	return ExitUsage
+}
+
+type DeprecatedError struct {
+	Name      string
+	Alias     bool
+	Message   string
+	RemovedIn string
+	Version   string
+}
+
+func (e *DeprecatedError) Error() string {
+	kind := "command"
+	if e.Alias {
+		kind = "alias"
+	}
+	return fmt.Sprintf("%s %q was removed in %s (running %s), %s", kind, e.Name, e.RemovedIn, e.Version, e.Message)
+}
+
+type PanicError struct {
+	Value       interface{}
+	Stack       []byte
+	CommandPath string
+	Args        []string
+	ReportPath  string
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in %q: %v", e.CommandPath, e.Value)
+}
+
+func (e *PanicError) ExitCode() int {
+	return ExitSoftware
+}
+
+type InterruptedError struct {
+	Signal os.Signal
+	Err    error
+}
+
+func (e *InterruptedError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("interrupted by %v: %v", e.Signal, e.Err)
+	}
+	return fmt.Sprintf("interrupted by %v", e.Signal)
+}
+
+func (e *InterruptedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *InterruptedError) ExitCode() int {
+	if sig, ok := e.Signal.(syscall.Signal); ok {
+		return 128 + int(sig)
+	}
+	return ExitInterrupted
+}
+
+type TimeoutError struct {
+	CommandPath string
+	Phase       string
+	Timeout     time.Duration
+	Err         error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%q timed out after %s during %s", e.CommandPath, e.Timeout, e.Phase)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+func (e *TimeoutError) ExitCode() int {
+	return ExitTimeout
+}
+
+type InvokeDepthError struct {
+	Depth int
+	Args  []string
+}
+
+func (e *InvokeDepthError) Error() string {
+	return fmt.Sprintf("invoke depth limit of %d exceeded running %q, commands may be invoking each other in a cycle", e.Depth, strings.Join(e.Args, " "))
+}
+
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid command tree:\n  - %s", strings.Join(e.Problems, "\n  - "))
+}
+
+type ArgError struct {
+	Position int
+	Name     string
+	Value    string
+	Reason   string
+}
+
+func (e *ArgError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("%s %q at position %d", e.Reason, e.Value, e.Position)
+	}
+	return fmt.Sprintf("argument <%s> at position %d: %s", e.Name, e.Position, e.Reason)
+}
+
+func (e *ArgError) ExitCode() int {
+	return ExitUsage
+}
+
+type ConfigKeyError struct {
+	Key    string
+	Source string
+	Reason string
+}
+
+func (e *ConfigKeyError) Error() string {
+	if e.Source == "" {
+		return fmt.Sprintf("config key %q: %s", e.Key, e.Reason)
+	}
+	return fmt.Sprintf("config key %q from %s: %s", e.Key, e.Source, e.Reason)
+}
+
+func (e *ConfigKeyError) ExitCode() int {
+	return ExitConfig
+}
+
+type MissingConfigKey struct {
+	Key   string
+	Flag  string
+	Env   []string
+	Files []string
+}
+
+type MissingConfigError struct {
+	Keys []MissingConfigKey
+}
+
+func (e *MissingConfigError) Error() string {
+	var b strings.Builder
+	b.WriteString("missing required config:")
+
+	for _, key := range e.Keys {
+		hints := make([]string, 0, 3)
+		if key.Flag != "" {
+			hints = append(hints, "flag "+key.Flag)
+		}
+		if len(key.Env) > 0 {
+			hints = append(hints, "env "+strings.Join(key.Env, ", "))
+		}
+		if len(key.Files) > 0 {
+			hints = append(hints, "file "+strings.Join(key.Files, ", "))
+		}
+
+		fmt.Fprintf(&b, "\n  - %s", key.Key)
+		if len(hints) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(hints, "; "))
+		}
+	}
+
+	return b.String()
+}
+
+func (e *MissingConfigError) ExitCode() int {
+	return ExitConfig
+}
@@ -4,6 +4,10 @@ go 1.24.2
 
 require github.com/gnemade360/go-cli v0.1.0
 
-require github.com/gnemade360/go-config v0.1.3 // indirect
+require (
+	github.com/gnemade360/go-config v0.1.3 // indirect
+	github.com/gnemade360/go-map-navigator v0.1.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
 
 replace github.com/gnemade360/go-cli => ../..
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/gnemade360/go-cli"
@@ -50,16 +49,25 @@ func main() {
 	errorCmd := gocli.NewCommand(
 		gocli.WithName("error"),
 		gocli.WithShort("Demonstrates error handling in hooks"),
+		gocli.WithPostRunOnFailure(),
 		gocli.WithPreRun(func(cmd *gocli.Command, args []string) error {
 			fmt.Println("🔧 [PreRun] Starting initialization...")
 			return nil
 		}),
 		gocli.WithRun(func(cmd *gocli.Command, args []string) error {
+			cmd.Defer(func() error {
+				fmt.Println("🧹 [Defer] Releasing resources acquired in Run")
+				return nil
+			})
 			fmt.Println("⚙️  [Run] Simulating an error...")
 			return fmt.Errorf("something went wrong during execution")
 		}),
 		gocli.WithPostRun(func(cmd *gocli.Command, args []string) error {
-			fmt.Println("🧹 [PostRun] This won't execute due to Run error")
+			fmt.Println("🧹 [PostRun] Runs after the Run error because of WithPostRunOnFailure")
+			return nil
+		}),
+		gocli.WithFinally(func(cmd *gocli.Command, args []string) error {
+			fmt.Println("🧹 [Finally] Always runs, even after the Run error")
 			return nil
 		}),
 	)
@@ -69,10 +77,7 @@ func main() {
 	fmt.Println("=== go-cli Lifecycle Demo ===")
 	fmt.Println()
 
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "\n❌ Error: %v\n", err)
-		os.Exit(1)
-	}
+	gocli.Main(rootCmd)
 
 	fmt.Println("✅ Application finished successfully!")
 }
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/gnemade360/go-cli"
@@ -60,10 +59,7 @@ func main() {
 		}),
 	)
 
-	rootCmd.AddCommand(versionCmd, echoCmd, uppercaseCmd, lowercaseCmd)
+	rootCmd.AddCommand(versionCmd, echoCmd, uppercaseCmd, lowercaseCmd, gocli.NewCompletionCommand())
 
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
-	}
+	gocli.Main(rootCmd)
 }
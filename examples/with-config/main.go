@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/gnemade360/go-cli"
 	"github.com/gnemade360/go-config/configutil"
@@ -64,8 +63,5 @@ func main() {
 
 	rootCmd.AddCommand(configCmd, connectCmd)
 
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
-	}
+	gocli.Main(rootCmd)
 }
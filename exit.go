@@ -0,0 +1,60 @@
+package gocli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ExitUsage follows the shell convention for command misuse, ExitSoftware
+// and ExitConfig are EX_SOFTWARE and EX_CONFIG from sysexits.h, ExitTimeout
+// matches timeout(1) and ExitInterrupted is 128+SIGINT.
+const (
+	ExitOK          = 0
+	ExitError       = 1
+	ExitUsage       = 2
+	ExitSoftware    = 70
+	ExitConfig      = 78
+	ExitTimeout     = 124
+	ExitInterrupted = 130
+)
+
+type ExitCoder interface {
+	ExitCode() int
+}
+
+var osExit = os.Exit
+
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var coder ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+
+	return ExitError
+}
+
+func Main(root *Command) {
+	err := root.Execute()
+	if err == nil {
+		return
+	}
+
+	code := ExitCode(err)
+
+	// A recovered panic has already been reported to the user.
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		w := root.ErrOrStderr()
+		fmt.Fprintf(w, "Error: %v\n", err)
+		if code == ExitUsage {
+			fmt.Fprintf(w, "Run '%s --help' for usage.\n", root.CommandPath())
+		}
+	}
+
+	osExit(code)
+}
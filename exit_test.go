@@ -0,0 +1,91 @@
+package gocli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type customExitError struct{}
+
+func (e *customExitError) Error() string { return "custom" }
+
+func (e *customExitError) ExitCode() int { return 42 }
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"plain error", errors.New("failed"), ExitError},
+		{"invalid args", &InvalidArgsError{Expected: "1", Received: 0}, ExitUsage},
+		{"invalid arg", &InvalidArgError{Arg: "x"}, ExitUsage},
+		{"unknown command", &UnknownCommandError{Command: "app", Name: "x"}, ExitUsage},
+		{"unknown flag", &UnknownFlagError{Flag: "--x"}, ExitUsage},
+		{"panic", &PanicError{Value: "boom"}, ExitSoftware},
+		{"wrapped", fmt.Errorf("preRun failed: %w", &InvalidArgError{Arg: "x"}), ExitUsage},
+		{"custom", fmt.Errorf("run failed: %w", &customExitError{}), 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMain_ExitCodes(t *testing.T) {
+	oldExit := osExit
+	defer func() { osExit = oldExit }()
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantOut  []string
+	}{
+		{"success", []string{"ok"}, -1, nil},
+		{"run error", []string{"fail"}, ExitError, []string{"Error: run failed: failed"}},
+		{"usage error", []string{"unknown"}, ExitUsage, []string{`Error: unknown command "unknown" for "app"`, "Run 'app --help' for usage."}},
+		{"panic", []string{"crash"}, ExitSoftware, []string{"app crash crashed unexpectedly: boom"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := -1
+			osExit = func(c int) { code = c }
+
+			var errOut bytes.Buffer
+			rootCmd := NewCommand(WithName("app"), WithPanicRecovery())
+			rootCmd.AddCommand(
+				NewCommand(WithName("ok"), WithRun(func(cmd *Command, args []string) error { return nil })),
+				NewCommand(WithName("fail"), WithRun(func(cmd *Command, args []string) error { return errors.New("failed") })),
+				NewCommand(WithName("crash"), WithRun(func(cmd *Command, args []string) error { panic("boom") })),
+			)
+			rootCmd.SetArgs(tt.args)
+			rootCmd.SetErr(&errOut)
+
+			Main(rootCmd)
+
+			if code != tt.wantCode {
+				t.Errorf("expected exit code %d, got %d", tt.wantCode, code)
+			}
+
+			for _, want := range tt.wantOut {
+				if !strings.Contains(errOut.String(), want) {
+					t.Errorf("expected stderr to contain %q, got:\n%s", want, errOut.String())
+				}
+			}
+
+			if tt.name == "panic" && strings.Contains(errOut.String(), "Error:") {
+				t.Errorf("recovered panic should only be reported once, got:\n%s", errOut.String())
+			}
+		})
+	}
+}
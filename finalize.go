@@ -0,0 +1,34 @@
+package gocli
+
+import "errors"
+
+// Defer registers fn to run once the current execution finishes, whether it
+// succeeded, failed or panicked. Deferred functions run in LIFO order. Outside
+// of an execution Defer does nothing.
+func (c *Command) Defer(fn func() error) {
+	if c.inv != nil {
+		c.inv.addDeferred(fn)
+	}
+}
+
+func (c *Command) runFinalizers(args []string) error {
+	var deferred []func() error
+	if c.inv != nil {
+		deferred = c.inv.takeDeferred()
+	}
+
+	errs := make([]error, 0)
+	for i := len(deferred) - 1; i >= 0; i-- {
+		if err := deferred[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if c.finally != nil {
+		if err := c.finally(c, args); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
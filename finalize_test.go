@@ -0,0 +1,258 @@
+package gocli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCommand_FinalizersRunLIFO(t *testing.T) {
+	var order []string
+
+	cmd := NewCommand(
+		WithName("test"),
+		WithPreRun(func(cmd *Command, args []string) error {
+			cmd.Defer(func() error {
+				order = append(order, "close connection")
+				return nil
+			})
+			return nil
+		}),
+		WithRun(func(cmd *Command, args []string) error {
+			cmd.Defer(func() error {
+				order = append(order, "remove temp dir")
+				return nil
+			})
+			return nil
+		}),
+		WithFinally(func(cmd *Command, args []string) error {
+			order = append(order, "finally")
+			return nil
+		}),
+	)
+
+	cmd.SetArgs([]string{})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	expected := []string{"remove temp dir", "close connection", "finally"}
+	if strings.Join(order, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected order %v, got %v", expected, order)
+	}
+}
+
+func TestCommand_FinalizersRunAfterFailure(t *testing.T) {
+	runError := errors.New("run error")
+	cleanupError := errors.New("cleanup error")
+	finallyExecuted := false
+
+	cmd := NewCommand(
+		WithName("test"),
+		WithRun(func(cmd *Command, args []string) error {
+			cmd.Defer(func() error {
+				return cleanupError
+			})
+			return runError
+		}),
+		WithFinally(func(cmd *Command, args []string) error {
+			finallyExecuted = true
+			return nil
+		}),
+	)
+
+	cmd.SetArgs([]string{})
+
+	err := cmd.Execute()
+	if !errors.Is(err, runError) {
+		t.Errorf("expected run error to be preserved, got %v", err)
+	}
+
+	if !errors.Is(err, cleanupError) {
+		t.Errorf("expected cleanup error to be joined, got %v", err)
+	}
+
+	if !finallyExecuted {
+		t.Error("finally should execute after a run error")
+	}
+}
+
+func TestCommand_FinalizersRunAfterPreRunFailure(t *testing.T) {
+	finallyExecuted := false
+
+	cmd := NewCommand(
+		WithName("test"),
+		WithPreRun(func(cmd *Command, args []string) error {
+			return errors.New("preRun error")
+		}),
+		WithFinally(func(cmd *Command, args []string) error {
+			finallyExecuted = true
+			return nil
+		}),
+	)
+
+	cmd.SetArgs([]string{})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if !finallyExecuted {
+		t.Error("finally should execute after a preRun error")
+	}
+}
+
+func TestCommand_FinalizersRunOnPanic(t *testing.T) {
+	deferredExecuted := false
+	finallyExecuted := false
+
+	cmd := NewCommand(
+		WithName("test"),
+		WithRun(func(cmd *Command, args []string) error {
+			cmd.Defer(func() error {
+				deferredExecuted = true
+				return nil
+			})
+			panic("boom")
+		}),
+		WithFinally(func(cmd *Command, args []string) error {
+			finallyExecuted = true
+			return nil
+		}),
+	)
+
+	cmd.SetArgs([]string{})
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("expected panic to propagate, got %v", r)
+			}
+		}()
+		cmd.Execute()
+	}()
+
+	if !deferredExecuted || !finallyExecuted {
+		t.Errorf("expected finalizers to run on panic, deferred=%v finally=%v", deferredExecuted, finallyExecuted)
+	}
+}
+
+func TestCommand_DeferFromPersistentHook(t *testing.T) {
+	var order []string
+
+	rootCmd := NewCommand(
+		WithName("root"),
+		WithPersistentPreRun(func(cmd *Command, args []string) error {
+			cmd.Defer(func() error {
+				order = append(order, "root cleanup")
+				return nil
+			})
+			return nil
+		}),
+	)
+	rootCmd.AddCommand(NewCommand(
+		WithName("sub"),
+		WithRun(func(cmd *Command, args []string) error {
+			cmd.Defer(func() error {
+				order = append(order, "sub cleanup")
+				return nil
+			})
+			return nil
+		}),
+	))
+
+	rootCmd.SetArgs([]string{"sub"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if strings.Join(order, ",") != "sub cleanup,root cleanup" {
+		t.Errorf("expected sub cleanup before root cleanup, got %v", order)
+	}
+}
+
+func TestCommand_PostRunOnFailure(t *testing.T) {
+	runError := errors.New("run error")
+	postRunError := errors.New("post run error")
+
+	t.Run("post hooks run after run error", func(t *testing.T) {
+		var order []string
+		record := func(name string) CommandFunc {
+			return func(cmd *Command, args []string) error {
+				order = append(order, name)
+				return nil
+			}
+		}
+
+		rootCmd := NewCommand(
+			WithName("root"),
+			WithPersistentPostRun(record("root.persistentPostRun")),
+		)
+		rootCmd.AddCommand(NewCommand(
+			WithName("leaf"),
+			WithPostRunOnFailure(),
+			WithRun(func(cmd *Command, args []string) error {
+				order = append(order, "leaf.run")
+				return runError
+			}),
+			WithPostRun(record("leaf.postRun")),
+			WithFinally(record("leaf.finally")),
+		))
+		rootCmd.SetArgs([]string{"leaf"})
+
+		if err := rootCmd.Execute(); !errors.Is(err, runError) {
+			t.Fatalf("expected run error, got %v", err)
+		}
+
+		expected := "leaf.run,leaf.postRun,root.persistentPostRun,leaf.finally"
+		if got := strings.Join(order, ","); got != expected {
+			t.Errorf("expected order %s, got %s", expected, got)
+		}
+	})
+
+	t.Run("post run errors are joined", func(t *testing.T) {
+		cmd := NewCommand(
+			WithName("test"),
+			WithPostRunOnFailure(),
+			WithRun(func(cmd *Command, args []string) error {
+				return runError
+			}),
+			WithPostRun(func(cmd *Command, args []string) error {
+				return postRunError
+			}),
+		)
+		cmd.SetArgs([]string{})
+
+		err := cmd.Execute()
+		if !errors.Is(err, runError) || !errors.Is(err, postRunError) {
+			t.Errorf("expected run and post run errors, got %v", err)
+		}
+	})
+
+	t.Run("pre run error still skips post run", func(t *testing.T) {
+		postRunExecuted := false
+
+		cmd := NewCommand(
+			WithName("test"),
+			WithPostRunOnFailure(),
+			WithPreRun(func(cmd *Command, args []string) error {
+				return runError
+			}),
+			WithRun(func(cmd *Command, args []string) error { return nil }),
+			WithPostRun(func(cmd *Command, args []string) error {
+				postRunExecuted = true
+				return nil
+			}),
+		)
+		cmd.SetArgs([]string{})
+
+		if err := cmd.Execute(); !errors.Is(err, runError) {
+			t.Fatalf("expected pre run error, got %v", err)
+		}
+		if postRunExecuted {
+			t.Error("PostRun should not execute after a PreRun error")
+		}
+	})
+}
@@ -0,0 +1,369 @@
+package gocli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gnemade360/go-config/configprovider"
+	configerrors "github.com/gnemade360/go-config/errors"
+)
+
+type ValueType int
+
+const (
+	String ValueType = iota
+	Int
+	Bool
+	Duration
+	StringSlice
+)
+
+func (t ValueType) String() string {
+	switch t {
+	case String:
+		return "string"
+	case Int:
+		return "int"
+	case Bool:
+		return "bool"
+	case Duration:
+		return "duration"
+	case StringSlice:
+		return "strings"
+	default:
+		return "unknown"
+	}
+}
+
+func (t ValueType) parse(raw string) (interface{}, error) {
+	switch t {
+	case String:
+		return raw, nil
+	case Int:
+		return strconv.Atoi(raw)
+	case Bool:
+		return strconv.ParseBool(raw)
+	case Duration:
+		return time.ParseDuration(raw)
+	case StringSlice:
+		return strings.Split(raw, ","), nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %d", t)
+	}
+}
+
+type Flag struct {
+	Name      string
+	Shorthand string
+	Usage     string
+	Type      ValueType
+	Default   interface{}
+}
+
+func StringFlag(name, value, usage string) Flag {
+	return Flag{Name: name, Usage: usage, Type: String, Default: value}
+}
+
+func IntFlag(name string, value int, usage string) Flag {
+	return Flag{Name: name, Usage: usage, Type: Int, Default: value}
+}
+
+func BoolFlag(name string, value bool, usage string) Flag {
+	return Flag{Name: name, Usage: usage, Type: Bool, Default: value}
+}
+
+func DurationFlag(name string, value time.Duration, usage string) Flag {
+	return Flag{Name: name, Usage: usage, Type: Duration, Default: value}
+}
+
+func StringSliceFlag(name string, value []string, usage string) Flag {
+	return Flag{Name: name, Usage: usage, Type: StringSlice, Default: value}
+}
+
+func (f Flag) WithShorthand(shorthand string) Flag {
+	f.Shorthand = shorthand
+	return f
+}
+
+func (c *Command) Flags() []Flag {
+	return c.flags
+}
+
+func (c *Command) FlagChanged(name string) bool {
+	_, ok := c.flagValues[name]
+	return ok
+}
+
+func (c *Command) PersistentFlags() []Flag {
+	return c.persistentFlags
+}
+
+func (c *Command) InheritedFlags() []Flag {
+	if c.parent == nil {
+		return nil
+	}
+
+	return append(c.parent.InheritedFlags(), c.parent.persistentFlags...)
+}
+
+func (c *Command) LocalFlags() []Flag {
+	return append(append([]Flag{}, c.flags...), c.persistentFlags...)
+}
+
+func (c *Command) visibleFlags() []Flag {
+	return append(c.LocalFlags(), c.InheritedFlags()...)
+}
+
+func (c *Command) checkFlagConflicts() error {
+	if c.parent != nil {
+		if err := c.parent.checkFlagConflicts(); err != nil {
+			return err
+		}
+	}
+
+	for _, inherited := range c.InheritedFlags() {
+		for _, local := range c.LocalFlags() {
+			if local.Name == inherited.Name ||
+				(local.Shorthand != "" && local.Shorthand == inherited.Shorthand) {
+				return &FlagConflictError{
+					Flag:     local.Name,
+					Command:  c.CommandPath(),
+					Ancestor: c.persistentFlagOwner(inherited.Name).CommandPath(),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Command) persistentFlagOwner(name string) *Command {
+	for p := c.parent; p != nil; p = p.parent {
+		for _, f := range p.persistentFlags {
+			if f.Name == name {
+				return p
+			}
+		}
+	}
+	return c
+}
+
+func (c *Command) flagNeedsValue(arg string) bool {
+	if strings.Contains(arg, "=") {
+		return false
+	}
+
+	var (
+		flag  Flag
+		found bool
+	)
+	if strings.HasPrefix(arg, "--") {
+		flag, found = c.lookupFlag(arg[2:])
+	} else {
+		flag, found = c.lookupShorthand(arg[1:])
+	}
+
+	return found && flag.Type != Bool
+}
+
+func (c *Command) lookupFlag(name string) (Flag, bool) {
+	for _, f := range c.visibleFlags() {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Flag{}, false
+}
+
+func (c *Command) lookupShorthand(shorthand string) (Flag, bool) {
+	for _, f := range c.visibleFlags() {
+		if f.Shorthand != "" && f.Shorthand == shorthand {
+			return f, true
+		}
+	}
+	return Flag{}, false
+}
+
+func (c *Command) parseFlags(args []string) ([]string, error) {
+	values := make(map[string]interface{})
+	positionals := make([]string, 0, len(args))
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" {
+			positionals = append(positionals, args[i+1:]...)
+			break
+		}
+
+		if !isFlagArg(arg) {
+			positionals = append(positionals, arg)
+			continue
+		}
+
+		var (
+			flag     Flag
+			found    bool
+			display  string
+			raw      string
+			hasValue bool
+		)
+
+		if strings.HasPrefix(arg, "--") {
+			name := arg[2:]
+			if idx := strings.Index(name, "="); idx >= 0 {
+				name, raw, hasValue = name[:idx], name[idx+1:], true
+			}
+			display = "--" + name
+			flag, found = c.lookupFlag(name)
+		} else {
+			name := arg[1:]
+			if idx := strings.Index(name, "="); idx >= 0 {
+				name, raw, hasValue = name[:idx], name[idx+1:], true
+			}
+			display = "-" + name
+			flag, found = c.lookupShorthand(name)
+		}
+
+		if !found {
+			return nil, &UnknownFlagError{Flag: display}
+		}
+
+		if !hasValue {
+			if flag.Type == Bool {
+				raw, hasValue = "true", true
+			} else if i+1 < len(args) {
+				i++
+				raw, hasValue = args[i], true
+			}
+		}
+
+		if !hasValue {
+			return nil, &MissingFlagValueError{Flag: display}
+		}
+
+		value, err := flag.Type.parse(raw)
+		if err != nil {
+			return nil, &InvalidFlagValueError{Flag: display, Value: raw, Expected: flag.Type.String()}
+		}
+
+		if flag.Type == StringSlice {
+			if prev, ok := values[flag.Name].([]string); ok {
+				value = append(prev, value.([]string)...)
+			}
+		}
+
+		values[flag.Name] = value
+	}
+
+	c.flagValues = values
+	c.shareInheritedFlagValues(values)
+	return positionals, nil
+}
+
+// shareInheritedFlagValues stores the values of inherited flags on the
+// ancestors that see them too, so Root().Config() and FlagChanged agree with
+// the command that parsed them. Ancestors are bound to the invocation here,
+// so the shared tree is not touched.
+func (c *Command) shareInheritedFlagValues(values map[string]interface{}) {
+	for anc := c.parent; anc != nil; anc = anc.parent {
+		shared := make(map[string]interface{})
+		for _, f := range append(anc.PersistentFlags(), anc.InheritedFlags()...) {
+			if value, ok := values[f.Name]; ok {
+				shared[f.Name] = value
+			}
+		}
+		anc.flagValues = shared
+	}
+}
+
+func isFlagArg(arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' {
+		return false
+	}
+	if _, err := strconv.ParseFloat(arg, 64); err == nil {
+		return false
+	}
+	return true
+}
+
+type flagProvider struct {
+	cmd  *Command
+	next configprovider.Provider
+}
+
+func (p *flagProvider) Read(key string) (interface{}, error) {
+	flag, ok := p.cmd.lookupFlag(key)
+	if !ok {
+		return p.readNext(key)
+	}
+
+	if value, ok := p.cmd.flagValues[key]; ok {
+		return value, nil
+	}
+
+	if value, err := p.readNext(key); err == nil {
+		return value, nil
+	}
+
+	if flag.Default != nil {
+		return flag.Default, nil
+	}
+
+	return nil, &configerrors.ConfigNotFoundError{Key: key}
+}
+
+func (p *flagProvider) readNext(key string) (interface{}, error) {
+	if p.next == nil {
+		return nil, &configerrors.ConfigNotFoundError{Key: key}
+	}
+	return p.next.Read(key)
+}
+
+func (f Flag) usageLine() string {
+	var b strings.Builder
+
+	if f.Shorthand != "" {
+		fmt.Fprintf(&b, "-%s, --%s", f.Shorthand, f.Name)
+	} else {
+		fmt.Fprintf(&b, "    --%s", f.Name)
+	}
+
+	if f.Type != Bool {
+		b.WriteString(" " + f.Type.String())
+	}
+
+	return b.String()
+}
+
+func (f Flag) defaultText() string {
+	switch v := f.Default.(type) {
+	case nil:
+		return ""
+	case string:
+		if v == "" {
+			return ""
+		}
+		return fmt.Sprintf(" (default %q)", v)
+	case bool:
+		if !v {
+			return ""
+		}
+	case int:
+		if v == 0 {
+			return ""
+		}
+	case time.Duration:
+		if v == 0 {
+			return ""
+		}
+	case []string:
+		if len(v) == 0 {
+			return ""
+		}
+		return fmt.Sprintf(" (default [%s])", strings.Join(v, ","))
+	}
+	return fmt.Sprintf(" (default %v)", f.Default)
+}
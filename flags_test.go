@@ -0,0 +1,406 @@
+package gocli
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gnemade360/go-config/configutil"
+	configerrors "github.com/gnemade360/go-config/errors"
+)
+
+func TestCommand_ParseFlags(t *testing.T) {
+	var (
+		gotArgs    []string
+		gotHost    string
+		gotPort    int
+		gotVerbose bool
+		gotTimeout time.Duration
+		gotTags    []string
+	)
+
+	cmd := NewCommand(
+		WithName("serve"),
+		WithFlag(
+			StringFlag("database.host", "localhost", "Database host"),
+			IntFlag("port", 8080, "Port to listen on").WithShorthand("p"),
+			BoolFlag("verbose", false, "Enable verbose output").WithShorthand("v"),
+			DurationFlag("timeout", 30*time.Second, "Request timeout"),
+			StringSliceFlag("tag", nil, "Tags to apply"),
+		),
+		WithRun(func(cmd *Command, args []string) error {
+			cfg := cmd.Config()
+			gotArgs = args
+			gotHost = configutil.GetString(cfg, "database.host", "")
+			gotPort = configutil.GetInt(cfg, "port", 0)
+			gotVerbose = configutil.GetBool(cfg, "verbose", false)
+			gotTimeout = configutil.GetDuration(cfg, "timeout", 0)
+			gotTags = configutil.GetStringSlice(cfg, "tag", nil)
+			return nil
+		}),
+	)
+
+	cmd.SetArgs([]string{
+		"first",
+		"--database.host=db.example.com",
+		"-p", "9000",
+		"-v",
+		"--timeout", "5s",
+		"--tag", "a,b", "--tag=c",
+		"second", "--", "--not-a-flag",
+	})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if want := []string{"first", "second", "--not-a-flag"}; !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("expected args %v, got %v", want, gotArgs)
+	}
+	if gotHost != "db.example.com" {
+		t.Errorf("expected host 'db.example.com', got '%s'", gotHost)
+	}
+	if gotPort != 9000 {
+		t.Errorf("expected port 9000, got %d", gotPort)
+	}
+	if !gotVerbose {
+		t.Error("expected verbose to be true")
+	}
+	if gotTimeout != 5*time.Second {
+		t.Errorf("expected timeout 5s, got %v", gotTimeout)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(gotTags, want) {
+		t.Errorf("expected tags %v, got %v", want, gotTags)
+	}
+}
+
+func TestCommand_FlagDefaults(t *testing.T) {
+	var gotHost string
+	var gotPort int
+
+	cmd := NewCommand(
+		WithName("serve"),
+		WithFlag(
+			StringFlag("database.host", "localhost", "Database host"),
+			IntFlag("port", 8080, "Port to listen on"),
+		),
+		WithRun(func(cmd *Command, args []string) error {
+			gotHost = configutil.GetString(cmd.Config(), "database.host", "")
+			gotPort = configutil.GetInt(cmd.Config(), "port", 0)
+			return nil
+		}),
+	)
+
+	cmd.SetArgs([]string{})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if gotHost != "localhost" {
+		t.Errorf("expected default host 'localhost', got '%s'", gotHost)
+	}
+	if gotPort != 8080 {
+		t.Errorf("expected default port 8080, got %d", gotPort)
+	}
+}
+
+func TestCommand_FlagProviderPrecedence(t *testing.T) {
+	provider := &mapConfigProvider{values: map[string]interface{}{
+		"database.host": "config.example.com",
+		"database.user": "admin",
+	}}
+
+	var gotHost, gotUser string
+
+	rootCmd := NewCommand(
+		WithName("root"),
+		WithConfigProvider(provider),
+	)
+	rootCmd.AddCommand(NewCommand(
+		WithName("serve"),
+		WithFlag(StringFlag("database.host", "localhost", "Database host")),
+		WithRun(func(cmd *Command, args []string) error {
+			gotHost = configutil.GetString(cmd.Config(), "database.host", "")
+			gotUser = configutil.GetString(cmd.Config(), "database.user", "")
+			return nil
+		}),
+	))
+
+	t.Run("provider beats flag default", func(t *testing.T) {
+		rootCmd.SetArgs([]string{"serve"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if gotHost != "config.example.com" {
+			t.Errorf("expected host from provider, got '%s'", gotHost)
+		}
+	})
+
+	t.Run("explicit flag beats provider", func(t *testing.T) {
+		rootCmd.SetArgs([]string{"serve", "--database.host", "flag.example.com"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if gotHost != "flag.example.com" {
+			t.Errorf("expected host from flag, got '%s'", gotHost)
+		}
+		if gotUser != "admin" {
+			t.Errorf("expected undeclared key to fall through to provider, got '%s'", gotUser)
+		}
+	})
+}
+
+func TestCommand_FlagErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		target interface{}
+	}{
+		{"unknown long flag", []string{"--nope"}, new(*UnknownFlagError)},
+		{"unknown shorthand", []string{"-x"}, new(*UnknownFlagError)},
+		{"missing value", []string{"--port"}, new(*MissingFlagValueError)},
+		{"invalid int", []string{"--port=abc"}, new(*InvalidFlagValueError)},
+		{"invalid duration", []string{"--timeout", "soon"}, new(*InvalidFlagValueError)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executed := false
+			cmd := NewCommand(
+				WithName("serve"),
+				WithFlag(
+					IntFlag("port", 8080, "Port to listen on").WithShorthand("p"),
+					DurationFlag("timeout", 30*time.Second, "Request timeout"),
+				),
+				WithRun(func(cmd *Command, args []string) error {
+					executed = true
+					return nil
+				}),
+			)
+
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !errors.As(err, tt.target) {
+				t.Errorf("unexpected error type %T: %v", err, err)
+			}
+			if executed {
+				t.Error("run should not execute when flag parsing fails")
+			}
+		})
+	}
+}
+
+func TestCommand_NegativeNumberIsPositional(t *testing.T) {
+	var gotArgs []string
+	cmd := NewCommand(
+		WithName("serve"),
+		WithFlag(IntFlag("port", 8080, "Port to listen on").WithShorthand("p")),
+		WithRun(func(cmd *Command, args []string) error {
+			gotArgs = args
+			return nil
+		}),
+	)
+
+	cmd.SetArgs([]string{"-5"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(gotArgs, []string{"-5"}) {
+		t.Errorf("expected args [-5], got %v", gotArgs)
+	}
+}
+
+func TestCommand_FlagHelp(t *testing.T) {
+	cmd := NewCommand(
+		WithName("serve"),
+		WithFlag(
+			StringFlag("database.host", "localhost", "Database host"),
+			IntFlag("port", 8080, "Port to listen on").WithShorthand("p"),
+			BoolFlag("verbose", false, "Enable verbose output").WithShorthand("v"),
+			DurationFlag("timeout", 30*time.Second, "Request timeout"),
+		),
+	)
+	help := cmd.HelpString()
+
+	expected := []string{
+		"serve [flags]",
+		`--database.host string   Database host (default "localhost")`,
+		"-p, --port int",
+		"(default 8080)",
+		"-v, --verbose",
+		"--timeout duration",
+		"-h, --help",
+	}
+
+	for _, want := range expected {
+		if !strings.Contains(help, want) {
+			t.Errorf("expected help to contain %q, got:\n%s", want, help)
+		}
+	}
+}
+
+type mapConfigProvider struct {
+	values map[string]interface{}
+}
+
+func (m *mapConfigProvider) Read(key string) (interface{}, error) {
+	if value, ok := m.values[key]; ok {
+		return value, nil
+	}
+	return nil, &configerrors.ConfigNotFoundError{Key: key}
+}
+
+func TestCommand_PersistentFlags(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantArgs    []string
+		wantVerbose bool
+		wantOutput  string
+		wantSteps   int
+	}{
+		{"before subcommand", []string{"--verbose", "-o", "json", "db", "migrate", "up"}, []string{"up"}, true, "json", 0},
+		{"between subcommands", []string{"db", "--output=yaml", "migrate", "--steps", "2"}, []string{}, false, "yaml", 2},
+		{"after target", []string{"db", "migrate", "up", "-v", "--steps=3"}, []string{"up"}, true, "text", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				gotArgs        []string
+				gotVerbose     bool
+				gotOutput      string
+				gotSteps       int
+				gotRootOutput  string
+				gotRootChanged bool
+			)
+
+			rootCmd := NewCommand(
+				WithName("app"),
+				WithPersistentFlag(
+					BoolFlag("verbose", false, "Enable verbose output").WithShorthand("v"),
+					StringFlag("output", "text", "Output format").WithShorthand("o"),
+				),
+			)
+			groupCmd := NewCommand(WithName("db"))
+			groupCmd.AddCommand(NewCommand(
+				WithName("migrate"),
+				WithFlag(IntFlag("steps", 0, "Number of steps")),
+				WithRun(func(cmd *Command, args []string) error {
+					gotArgs = args
+					gotVerbose = configutil.GetBool(cmd.Config(), "verbose", false)
+					gotOutput = configutil.GetString(cmd.Config(), "output", "")
+					gotSteps = configutil.GetInt(cmd.Config(), "steps", -1)
+					gotRootOutput = configutil.GetString(cmd.Root().Config(), "output", "")
+					gotRootChanged = cmd.Root().FlagChanged("output")
+					return nil
+				}),
+			))
+			rootCmd.AddCommand(groupCmd)
+
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("expected args %v, got %v", tt.wantArgs, gotArgs)
+			}
+			if gotVerbose != tt.wantVerbose {
+				t.Errorf("expected verbose=%t, got %t", tt.wantVerbose, gotVerbose)
+			}
+			if gotOutput != tt.wantOutput {
+				t.Errorf("expected output '%s', got '%s'", tt.wantOutput, gotOutput)
+			}
+			if gotSteps != tt.wantSteps {
+				t.Errorf("expected steps %d, got %d", tt.wantSteps, gotSteps)
+			}
+			if gotRootOutput != tt.wantOutput || gotRootChanged != (tt.wantOutput != "text") {
+				t.Errorf("expected the root to see output '%s', got '%s' (changed=%t)", tt.wantOutput, gotRootOutput, gotRootChanged)
+			}
+		})
+	}
+}
+
+func TestCommand_PersistentFlagConflict(t *testing.T) {
+	executed := false
+	rootCmd := NewCommand(
+		WithName("app"),
+		WithPersistentFlag(StringFlag("output", "text", "Output format").WithShorthand("o")),
+	)
+	rootCmd.AddCommand(NewCommand(
+		WithName("status"),
+		WithFlag(StringFlag("output", "table", "Output format")),
+		WithRun(func(cmd *Command, args []string) error {
+			executed = true
+			return nil
+		}),
+	))
+
+	rootCmd.SetArgs([]string{"status"})
+
+	err := rootCmd.Execute()
+
+	var conflictErr *FlagConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("expected FlagConflictError, got %v", err)
+	}
+	if conflictErr.Flag != "output" || conflictErr.Command != "app status" || conflictErr.Ancestor != "app" {
+		t.Errorf("unexpected conflict details: %+v", conflictErr)
+	}
+	if executed {
+		t.Error("run should not execute when flags conflict")
+	}
+}
+
+func TestCommand_PersistentFlagHelp(t *testing.T) {
+	rootCmd := NewCommand(
+		WithName("app"),
+		WithPersistentFlag(
+			BoolFlag("verbose", false, "Enable verbose output").WithShorthand("v"),
+			StringFlag("config", "", "Path to config file"),
+			StringFlag("output", "text", "Output format").WithShorthand("o"),
+		),
+	)
+	groupCmd := NewCommand(WithName("db"))
+	leafCmd := NewCommand(
+		WithName("migrate"),
+		WithFlag(IntFlag("steps", 0, "Number of steps")),
+	)
+	groupCmd.AddCommand(leafCmd)
+	rootCmd.AddCommand(groupCmd)
+
+	rootHelp := rootCmd.HelpString()
+	if strings.Contains(rootHelp, "Global Flags:") {
+		t.Errorf("root help should not have a Global Flags section, got:\n%s", rootHelp)
+	}
+	if !strings.Contains(rootHelp, "-v, --verbose") {
+		t.Errorf("root help should list its persistent flags, got:\n%s", rootHelp)
+	}
+
+	leafHelp := leafCmd.HelpString()
+	flagsIdx := strings.Index(leafHelp, "Flags:")
+	globalIdx := strings.Index(leafHelp, "Global Flags:")
+	if flagsIdx < 0 || globalIdx < 0 {
+		t.Fatalf("expected Flags and Global Flags sections, got:\n%s", leafHelp)
+	}
+
+	local := leafHelp[flagsIdx:globalIdx]
+	global := leafHelp[globalIdx:]
+	if !strings.Contains(local, "--steps int") || strings.Contains(local, "--verbose") {
+		t.Errorf("unexpected local flags section:\n%s", local)
+	}
+	if !strings.Contains(global, "-o, --output string") || !strings.Contains(global, "--config string") {
+		t.Errorf("unexpected global flags section:\n%s", global)
+	}
+}
@@ -0,0 +1,595 @@
+package gocli
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gnemade360/go-config/configprovider"
+)
+
+type Runner interface {
+	Run(ctx context.Context) error
+}
+
+type structFieldKind int
+
+const (
+	structArg structFieldKind = iota
+	structFlag
+	structConfig
+	structParent
+)
+
+type structField struct {
+	index     []int
+	kind      structFieldKind
+	name      string
+	configKey string
+}
+
+// structBinder connects a command to the struct it was built from. The struct
+// passed to FromStruct is a template: every execution populates and runs a
+// fresh copy of it, so the command can be executed concurrently.
+type structBinder struct {
+	cmd    *Command
+	value  reflect.Value
+	fields []structField
+
+	parent      *structBinder
+	parentIndex []int
+}
+
+// structKey stores the copy of a binder's struct for the current execution in
+// the command's context.
+type structKey struct {
+	binder *structBinder
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// flagValueTypes are the Go types the flag parser produces for each value
+// type, so that defaults of named types like `type Env string` are converted.
+var flagValueTypes = map[ValueType]reflect.Type{
+	String:      reflect.TypeOf(""),
+	Int:         reflect.TypeOf(0),
+	Bool:        reflect.TypeOf(false),
+	Duration:    durationType,
+	StringSlice: reflect.TypeOf([]string(nil)),
+}
+
+func MustFromStruct(v interface{}, opts ...CommandOption) *Command {
+	cmd, err := FromStruct(v, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+// FromStruct builds a command from a pointer to a struct. Fields tagged
+// cli:"arg,N" become positional arguments, cli:"flag" become flags, and
+// config:"key" fields are read from the command's config. Struct fields tagged
+// cli:"cmd" become subcommands, and cli:"parent" fields point to the struct of
+// an ancestor command. FromStruct works on a private copy of v, so v itself
+// is never modified. Each execution copies that template, populates the copy
+// and calls its Run method, if it has one.
+func FromStruct(v interface{}, opts ...CommandOption) (*Command, error) {
+	binder, err := newStructBinder(v, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return binder.cmd, nil
+}
+
+func newStructBinder(v interface{}, opts ...CommandOption) (*structBinder, error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("FromStruct expects a pointer to a struct, got %T", v)
+	}
+
+	template := reflect.New(value.Elem().Type())
+	copyStruct(template.Elem(), value.Elem())
+
+	binder := &structBinder{value: template.Elem()}
+	structType := binder.value.Type()
+
+	cmdOpts := []CommandOption{WithName(commandNameFor(structType.Name()))}
+	args := make(map[int]Arg)
+	subcommands := make([]*Command, 0)
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, hasTag := field.Tag.Lookup("cli")
+		if tag == "-" {
+			continue
+		}
+
+		kind, params := parseCLITag(tag)
+
+		if kind == "cmd" || (!hasTag && isSubcommandField(field.Type)) {
+			sub, err := binder.subcommand(field, i, params)
+			if err != nil {
+				return nil, err
+			}
+			subcommands = append(subcommands, sub)
+			continue
+		}
+
+		if kind == "parent" {
+			if field.Type.Kind() != reflect.Ptr || !isStructType(field.Type) {
+				return nil, fmt.Errorf("field %s.%s: parent fields must be pointers to structs", structType.Name(), field.Name)
+			}
+			binder.fields = append(binder.fields, structField{index: field.Index, kind: structParent})
+			continue
+		}
+
+		configKey := field.Tag.Get("config")
+		if !hasTag && configKey == "" {
+			continue
+		}
+
+		bound := structField{index: field.Index, configKey: configKey}
+
+		switch kind {
+		case "arg":
+			arg, position, err := structArgFor(field, params)
+			if err != nil {
+				return nil, fmt.Errorf("field %s.%s: %w", structType.Name(), field.Name, err)
+			}
+			if _, exists := args[position]; exists {
+				return nil, fmt.Errorf("field %s.%s: argument position %d is used more than once", structType.Name(), field.Name, position)
+			}
+			args[position] = arg
+			bound.kind = structArg
+			bound.name = arg.Name
+		case "flag":
+			flag, err := structFlagFor(field, binder.value.Field(i), params)
+			if err != nil {
+				return nil, fmt.Errorf("field %s.%s: %w", structType.Name(), field.Name, err)
+			}
+			if _, persistent := params["persistent"]; persistent {
+				cmdOpts = append(cmdOpts, WithPersistentFlag(flag))
+			} else {
+				cmdOpts = append(cmdOpts, WithFlag(flag))
+			}
+			bound.kind = structFlag
+			bound.name = flag.Name
+		case "":
+			if _, ok := valueTypeFor(field.Type); !ok {
+				return nil, fmt.Errorf("field %s.%s: unsupported type %s", structType.Name(), field.Name, field.Type)
+			}
+			bound.kind = structConfig
+		default:
+			return nil, fmt.Errorf("field %s.%s: unknown cli tag kind %q", structType.Name(), field.Name, kind)
+		}
+
+		binder.fields = append(binder.fields, bound)
+	}
+
+	if len(args) > 0 {
+		positions := make([]int, 0, len(args))
+		for position := range args {
+			positions = append(positions, position)
+		}
+		sort.Ints(positions)
+
+		declared := make([]Arg, 0, len(positions))
+		for i, position := range positions {
+			if position != i {
+				return nil, fmt.Errorf("struct %s: argument positions must start at 0 without gaps", structType.Name())
+			}
+			declared = append(declared, args[position])
+		}
+		cmdOpts = append(cmdOpts, WithArgs(declared...))
+	}
+
+	cmdOpts = append(cmdOpts, WithMiddleware(binder.middleware))
+
+	if _, ok := v.(Runner); ok {
+		cmdOpts = append(cmdOpts, WithRun(func(cmd *Command, args []string) error {
+			instance, ok := binder.instance(cmd)
+			if !ok {
+				return fmt.Errorf("struct %s was not populated for this execution", structType.Name())
+			}
+			return instance.Interface().(Runner).Run(cmd.Context())
+		}))
+	}
+
+	binder.cmd = NewCommand(append(cmdOpts, opts...)...)
+	binder.cmd.AddCommand(subcommands...)
+
+	return binder, nil
+}
+
+func (b *structBinder) subcommand(field reflect.StructField, i int, params map[string]string) (*Command, error) {
+	fieldValue := b.value.Field(i)
+	if !isStructType(field.Type) {
+		return nil, fmt.Errorf("field %s.%s: subcommands must be structs or pointers to structs", b.value.Type().Name(), field.Name)
+	}
+	if fieldValue.Kind() == reflect.Struct {
+		fieldValue = fieldValue.Addr()
+	} else if fieldValue.IsNil() {
+		fieldValue.Set(reflect.New(field.Type.Elem()))
+	}
+
+	opts := make([]CommandOption, 0, 2)
+	if name, ok := params["name"]; ok {
+		opts = append(opts, WithName(name))
+	} else {
+		opts = append(opts, WithName(kebabCase(field.Name)))
+	}
+	if usage := field.Tag.Get("usage"); usage != "" {
+		opts = append(opts, WithShort(usage))
+	}
+
+	sub, err := newStructBinder(fieldValue.Interface(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	sub.parent = b
+	sub.parentIndex = field.Index
+
+	return sub.cmd, nil
+}
+
+// middleware copies the template struct for the current execution and
+// populates the copy before the rest of the lifecycle. It is inherited, so
+// structs of ancestor commands are copied and populated as well; positional
+// arguments only belong to the command being executed. The copy is linked into
+// the copy of the parent struct, if the parent command was built from one.
+func (b *structBinder) middleware(next CommandFunc) CommandFunc {
+	return func(cmd *Command, args []string) error {
+		instance := reflect.New(b.value.Type())
+		copyStruct(instance.Elem(), b.value)
+
+		if err := b.populate(cmd, instance.Elem(), cmd.CommandPath() == b.cmd.CommandPath()); err != nil {
+			return err
+		}
+
+		if b.parent != nil {
+			if parent, ok := b.parent.instance(cmd); ok {
+				field := parent.Elem().FieldByIndex(b.parentIndex)
+				if field.Kind() == reflect.Ptr {
+					field.Set(instance)
+				} else {
+					field.Set(instance.Elem())
+				}
+			}
+		}
+
+		cmd.WithContextValue(structKey{b}, instance)
+		return next(cmd, args)
+	}
+}
+
+// instance returns the copy of the struct made for the current execution.
+func (b *structBinder) instance(cmd *Command) (reflect.Value, bool) {
+	instance, ok := cmd.Context().Value(structKey{b}).(reflect.Value)
+	return instance, ok
+}
+
+func (b *structBinder) populate(cmd *Command, value reflect.Value, isTarget bool) error {
+	cfg := cmd.Config()
+
+	for _, field := range b.fields {
+		var (
+			raw   interface{}
+			found bool
+		)
+
+		switch {
+		case field.kind == structParent:
+			b.setParent(cmd, value.FieldByIndex(field.index))
+			continue
+		case field.kind == structArg:
+			if !isTarget {
+				continue
+			}
+			raw = cmd.ArgValue(field.name)
+			found = raw != nil
+		case field.kind == structFlag && cmd.FlagChanged(field.name):
+			raw, found = readConfig(cfg, field.name)
+		case field.configKey != "":
+			raw, found = readConfig(cfg, field.configKey)
+			if !found && field.kind == structFlag {
+				raw, found = readConfig(cfg, field.name)
+			}
+		default:
+			raw, found = readConfig(cfg, field.name)
+		}
+
+		if !found {
+			continue
+		}
+
+		fieldValue := value.FieldByIndex(field.index)
+		if err := assignValue(fieldValue, raw); err != nil {
+			return fmt.Errorf("field %s.%s: %w", b.value.Type().Name(), b.value.Type().FieldByIndex(field.index).Name, err)
+		}
+		// Slices such as flag defaults must not be shared with the config.
+		detachValue(fieldValue)
+	}
+
+	return nil
+}
+
+// setParent points field at the copy of the nearest ancestor struct of the
+// field's type. It is left unchanged when no ancestor has that type.
+func (b *structBinder) setParent(cmd *Command, field reflect.Value) {
+	for ancestor := b.parent; ancestor != nil; ancestor = ancestor.parent {
+		if ancestor.value.Type() != field.Type().Elem() {
+			continue
+		}
+		if instance, ok := ancestor.instance(cmd); ok {
+			field.Set(instance)
+		}
+		return
+	}
+}
+
+// copyStruct copies src into dst. The slices, maps and struct pointers held in
+// exported fields are copied as well, so dst shares no state with src. Parent
+// fields are left as they are, since they point up the command tree.
+func copyStruct(dst, src reflect.Value) {
+	dst.Set(src)
+
+	for i := 0; i < dst.NumField(); i++ {
+		field := dst.Type().Field(i)
+		if !field.IsExported() || field.Tag.Get("cli") == "parent" {
+			continue
+		}
+		detachValue(dst.Field(i))
+	}
+}
+
+func detachValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		copyStruct(v, v)
+	case reflect.Ptr:
+		if !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+			copied := reflect.New(v.Type().Elem())
+			copyStruct(copied.Elem(), v.Elem())
+			v.Set(copied)
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			reflect.Copy(copied, v)
+			v.Set(copied)
+		}
+	case reflect.Map:
+		if !v.IsNil() {
+			copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+			iter := v.MapRange()
+			for iter.Next() {
+				copied.SetMapIndex(iter.Key(), iter.Value())
+			}
+			v.Set(copied)
+		}
+	}
+}
+
+func readConfig(cfg configprovider.Provider, key string) (interface{}, bool) {
+	if cfg == nil {
+		return nil, false
+	}
+	value, err := cfg.Read(key)
+	if err != nil || value == nil {
+		return nil, false
+	}
+	return value, true
+}
+
+func assignValue(field reflect.Value, raw interface{}) error {
+	value := reflect.ValueOf(raw)
+
+	if value.Type().AssignableTo(field.Type()) {
+		field.Set(value)
+		return nil
+	}
+
+	// Named types such as `type Env string` take the value of their kind.
+	if value.Kind() == field.Kind() && value.Type().ConvertibleTo(field.Type()) {
+		field.Set(value.Convert(field.Type()))
+		return nil
+	}
+
+	if s, ok := raw.(string); ok {
+		valueType, ok := valueTypeFor(field.Type())
+		if !ok {
+			return fmt.Errorf("unsupported type %s", field.Type())
+		}
+		parsed, err := valueType.parse(s)
+		if err != nil {
+			return fmt.Errorf("invalid %s value %q", valueType, s)
+		}
+		return assignValue(field, parsed)
+	}
+
+	if items, ok := raw.([]interface{}); ok && field.Type() == reflect.TypeOf([]string(nil)) {
+		strs := make([]string, 0, len(items))
+		for _, item := range items {
+			strs = append(strs, fmt.Sprint(item))
+		}
+		field.Set(reflect.ValueOf(strs))
+		return nil
+	}
+
+	if isNumeric(value.Kind()) && isNumeric(field.Kind()) {
+		field.Set(value.Convert(field.Type()))
+		return nil
+	}
+
+	return fmt.Errorf("cannot assign %T to %s", raw, field.Type())
+}
+
+func structArgFor(field reflect.StructField, params map[string]string) (Arg, int, error) {
+	position, err := strconv.Atoi(params["0"])
+	if err != nil {
+		return Arg{}, 0, fmt.Errorf(`arg tag needs a position, e.g. cli:"arg,0"`)
+	}
+
+	arg := Arg{Name: kebabCase(field.Name)}
+	if name, ok := params["name"]; ok {
+		arg.Name = name
+	}
+	_, arg.Required = params["required"]
+
+	switch field.Type {
+	case reflect.TypeOf([]string(nil)):
+		arg.Type, arg.Variadic = String, true
+	case reflect.TypeOf([]int(nil)):
+		arg.Type, arg.Variadic = Int, true
+	default:
+		valueType, ok := valueTypeFor(field.Type)
+		if !ok {
+			return Arg{}, 0, fmt.Errorf("unsupported type %s", field.Type)
+		}
+		arg.Type = valueType
+	}
+
+	return arg, position, nil
+}
+
+func structFlagFor(field reflect.StructField, value reflect.Value, params map[string]string) (Flag, error) {
+	valueType, ok := valueTypeFor(field.Type)
+	if !ok {
+		return Flag{}, fmt.Errorf("unsupported type %s", field.Type)
+	}
+
+	flag := Flag{
+		Name:      kebabCase(field.Name),
+		Shorthand: params["short"],
+		Usage:     field.Tag.Get("usage"),
+		Type:      valueType,
+		Default:   value.Convert(flagValueTypes[valueType]).Interface(),
+	}
+	if name, ok := params["name"]; ok {
+		flag.Name = name
+	}
+
+	if def, ok := field.Tag.Lookup("default"); ok {
+		parsed, err := valueType.parse(def)
+		if err != nil {
+			return Flag{}, fmt.Errorf("invalid default %q for %s flag", def, valueType)
+		}
+		flag.Default = parsed
+		if err := assignValue(value, parsed); err != nil {
+			return Flag{}, err
+		}
+	}
+
+	return flag, nil
+}
+
+// parseCLITag splits a tag like "flag,name=env,short=e" into its kind and
+// parameters. Bare words become parameters with an empty value, except the
+// first one after "arg", which is stored under "0" as the position.
+func parseCLITag(tag string) (string, map[string]string) {
+	params := make(map[string]string)
+	if tag == "" {
+		return "", params
+	}
+
+	parts := strings.Split(tag, ",")
+	kind := strings.TrimSpace(parts[0])
+
+	for i, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		key, value, hasValue := strings.Cut(part, "=")
+		switch {
+		case hasValue:
+			params[key] = value
+		case kind == "arg" && i == 0:
+			params["0"] = part
+		default:
+			params[part] = ""
+		}
+	}
+
+	return kind, params
+}
+
+func valueTypeFor(t reflect.Type) (ValueType, bool) {
+	if t == durationType {
+		return Duration, true
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return String, true
+	case reflect.Int:
+		return Int, true
+	case reflect.Bool:
+		return Bool, true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.String {
+			return StringSlice, true
+		}
+	}
+
+	return 0, false
+}
+
+func isStructType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// isSubcommandField reports whether an untagged field should become a
+// subcommand, which is the case for structs that implement Runner.
+func isSubcommandField(t reflect.Type) bool {
+	if t.Kind() != reflect.Ptr {
+		t = reflect.PointerTo(t)
+	}
+	return isStructType(t) && t.Implements(reflect.TypeOf((*Runner)(nil)).Elem())
+}
+
+func isNumeric(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func commandNameFor(typeName string) string {
+	for _, suffix := range []string{"Command", "Cmd"} {
+		if trimmed := strings.TrimSuffix(typeName, suffix); trimmed != "" {
+			typeName = trimmed
+		}
+	}
+	return kebabCase(typeName)
+}
+
+func kebabCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && !unicode.IsUpper(runes[i-1])
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (nextLower && unicode.IsUpper(runes[i-1])) {
+				b.WriteRune('-')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,338 @@
+package gocli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type deployCmd struct {
+	Service  string        `cli:"arg,0,required"`
+	Hosts    []string      `cli:"arg,1"`
+	Env      string        `cli:"flag,name=env,short=e" usage:"Target environment" default:"staging"`
+	DryRun   bool          `cli:"flag"`
+	Wait     time.Duration `cli:"flag" default:"30s"`
+	Region   string        `config:"deploy.region"`
+	Replicas int           `cli:"flag" config:"deploy.replicas"`
+	App      *appCmd       `cli:"parent"`
+
+	onRun func(d *deployCmd)
+}
+
+func (d *deployCmd) Run(ctx context.Context) error {
+	if d.onRun != nil {
+		d.onRun(d)
+	}
+	return nil
+}
+
+type appCmd struct {
+	Verbose bool `cli:"flag,persistent,short=v"`
+
+	Deploy *deployCmd `usage:"Deploy a service"`
+	Status statusCmd  `cli:"cmd,name=stat"`
+}
+
+type statusCmd struct{}
+
+func (s *statusCmd) Run(ctx context.Context) error {
+	return nil
+}
+
+func TestFromStruct(t *testing.T) {
+	provider := &mapConfigProvider{values: map[string]interface{}{
+		"deploy.region":   "eu-west-1",
+		"deploy.replicas": 3,
+	}}
+
+	var deploy *deployCmd
+	template := &deployCmd{onRun: func(d *deployCmd) { deploy = d }}
+	cmd, err := FromStruct(template, WithConfigProvider(provider))
+	if err != nil {
+		t.Fatalf("FromStruct failed: %v", err)
+	}
+
+	if cmd.Name() != "deploy" {
+		t.Errorf("expected name 'deploy', got %q", cmd.Name())
+	}
+
+	cmd.SetArgs([]string{"api", "a", "b", "-e", "prod", "--dry-run"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if deploy == nil {
+		t.Fatal("Run was not called")
+	}
+
+	if deploy == template || template.Service != "" || template.Env != "" {
+		t.Error("the struct passed to FromStruct should not be modified")
+	}
+
+	if deploy.Service != "api" || strings.Join(deploy.Hosts, ",") != "a,b" {
+		t.Errorf("expected args to be populated, got service=%q hosts=%v", deploy.Service, deploy.Hosts)
+	}
+
+	if deploy.Env != "prod" || !deploy.DryRun || deploy.Wait != 30*time.Second {
+		t.Errorf("expected flags to be populated, got env=%q dry-run=%v wait=%v", deploy.Env, deploy.DryRun, deploy.Wait)
+	}
+
+	if deploy.Region != "eu-west-1" || deploy.Replicas != 3 {
+		t.Errorf("expected config to be populated, got region=%q replicas=%d", deploy.Region, deploy.Replicas)
+	}
+
+	t.Run("flag wins over config", func(t *testing.T) {
+		cmd.SetArgs([]string{"api", "--replicas", "5"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if deploy.Replicas != 5 {
+			t.Errorf("expected replicas 5, got %d", deploy.Replicas)
+		}
+	})
+
+	t.Run("fields are reset between executions", func(t *testing.T) {
+		cmd.SetArgs([]string{"api"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if len(deploy.Hosts) != 0 || deploy.Env != "staging" || deploy.DryRun {
+			t.Errorf("expected values from the previous execution to be reset, got hosts=%v env=%q dry-run=%v", deploy.Hosts, deploy.Env, deploy.DryRun)
+		}
+	})
+}
+
+func TestFromStruct_Subcommands(t *testing.T) {
+	unset := &appCmd{}
+	if len(MustFromStruct(unset).Commands()) != 2 || unset.Deploy != nil {
+		t.Fatal("nil subcommand pointer should become a subcommand without being allocated")
+	}
+
+	var deploy *deployCmd
+	app := &appCmd{Deploy: &deployCmd{onRun: func(d *deployCmd) { deploy = d }}}
+	rootCmd := MustFromStruct(app)
+
+	if rootCmd.Name() != "app" {
+		t.Errorf("expected name 'app', got %q", rootCmd.Name())
+	}
+
+	names := make([]string, 0)
+	for _, sub := range rootCmd.Commands() {
+		names = append(names, sub.Name())
+	}
+	if strings.Join(names, ",") != "deploy,stat" {
+		t.Fatalf("expected subcommands [deploy stat], got %v", names)
+	}
+
+	if rootCmd.Commands()[0].Short() != "Deploy a service" {
+		t.Errorf("expected usage tag as short description, got %q", rootCmd.Commands()[0].Short())
+	}
+
+	rootCmd.SetArgs([]string{"deploy", "api", "-v"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if deploy == nil || deploy.Service != "api" {
+		t.Fatal("deploy subcommand did not run with its args")
+	}
+
+	if deploy.App == nil || !deploy.App.Verbose {
+		t.Fatal("parent struct should be populated from inherited flags")
+	}
+
+	if deploy.App.Deploy != deploy {
+		t.Error("parent struct should point to the executed subcommand")
+	}
+
+	if app.Verbose || app.Deploy.Service != "" {
+		t.Error("the structs passed to FromStruct should not be modified")
+	}
+}
+
+type environment string
+
+type releaseCmd struct {
+	Env   environment `cli:"flag" default:"staging"`
+	Stage environment `cli:"flag"`
+
+	onRun func(r *releaseCmd)
+}
+
+func (r *releaseCmd) Run(ctx context.Context) error {
+	r.onRun(r)
+	return nil
+}
+
+func TestFromStruct_NamedTypes(t *testing.T) {
+	var release *releaseCmd
+	cmd, err := FromStruct(&releaseCmd{Stage: "beta", onRun: func(r *releaseCmd) { release = r }})
+	if err != nil {
+		t.Fatalf("FromStruct failed: %v", err)
+	}
+
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if release.Env != "staging" || release.Stage != "beta" {
+		t.Errorf("expected defaults staging and beta, got %q and %q", release.Env, release.Stage)
+	}
+
+	cmd.SetArgs([]string{"--env", "prod"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if release.Env != "prod" {
+		t.Errorf("expected env prod, got %q", release.Env)
+	}
+}
+
+func TestFromStruct_ConcurrentExecutions(t *testing.T) {
+	var mu sync.Mutex
+	services := make(map[string]string)
+
+	cmd := MustFromStruct(&deployCmd{onRun: func(d *deployCmd) {
+		mu.Lock()
+		defer mu.Unlock()
+		services[d.Service] = d.Env
+	}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			service := fmt.Sprintf("svc%d", i)
+			inv := &Invocation{Args: []string{service, "--env", fmt.Sprintf("env%d", i)}}
+			if err := cmd.ExecuteInvocation(context.Background(), inv); err != nil {
+				t.Errorf("ExecuteInvocation failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < 20; i++ {
+		if env := services[fmt.Sprintf("svc%d", i)]; env != fmt.Sprintf("env%d", i) {
+			t.Errorf("expected svc%d to run with env%d, got %q", i, i, env)
+		}
+	}
+}
+
+type tagsCmd struct {
+	Env  string   `cli:"flag" default:"prod"`
+	Tags []string `cli:"flag" default:"a,b"`
+	Sub  *subCmd
+
+	onRun func(c *tagsCmd)
+}
+
+func (c *tagsCmd) Run(ctx context.Context) error {
+	c.onRun(c)
+	return nil
+}
+
+type subCmd struct {
+	Labels []string
+
+	onRun func(s *subCmd)
+}
+
+func (s *subCmd) Run(ctx context.Context) error {
+	s.onRun(s)
+	return nil
+}
+
+func TestFromStruct_CopiesDoNotShareState(t *testing.T) {
+	var tags, labels []string
+	template := &tagsCmd{
+		Sub: &subCmd{Labels: []string{"x"}, onRun: func(s *subCmd) {
+			labels = append(labels, s.Labels[0])
+			s.Labels[0] = "changed"
+		}},
+		onRun: func(c *tagsCmd) {
+			tags = append(tags, c.Tags[0])
+			c.Tags[0] = "changed"
+		},
+	}
+
+	cmd := MustFromStruct(template)
+
+	if template.Env != "" || template.Tags != nil {
+		t.Errorf("defaults should not be written to the struct passed in, got env=%q tags=%v", template.Env, template.Tags)
+	}
+
+	for _, args := range [][]string{{}, {}, {"sub"}, {"sub"}} {
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute %v failed: %v", args, err)
+		}
+	}
+
+	if strings.Join(tags, ",") != "a,a" || strings.Join(labels, ",") != "x,x" {
+		t.Errorf("expected every execution to start from the template, got tags=%v labels=%v", tags, labels)
+	}
+
+	if template.Sub.Labels[0] != "x" {
+		t.Errorf("nested state should not be shared with the struct passed in, got %v", template.Sub.Labels)
+	}
+}
+
+func TestFromStruct_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		message string
+	}{
+		{"not a pointer", deployCmd{}, "expects a pointer to a struct"},
+		{"unsupported type", &struct {
+			Limits map[string]int `cli:"flag"`
+		}{}, "unsupported type map[string]int"},
+		{"duplicate position", &struct {
+			A string `cli:"arg,0"`
+			B string `cli:"arg,0"`
+		}{}, "argument position 0 is used more than once"},
+		{"position gap", &struct {
+			A string `cli:"arg,1"`
+		}{}, "must start at 0 without gaps"},
+		{"bad default", &struct {
+			Port int `cli:"flag" default:"http"`
+		}{}, `invalid default "http" for int flag`},
+		{"parent not a pointer", &struct {
+			App appCmd `cli:"parent"`
+		}{}, "parent fields must be pointers to structs"},
+		{"unknown kind", &struct {
+			A string `cli:"option"`
+		}{}, `unknown cli tag kind "option"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromStruct(tt.value)
+			if err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("expected error containing %q, got %v", tt.message, err)
+			}
+		})
+	}
+}
+
+func TestKebabCase(t *testing.T) {
+	tests := map[string]string{
+		"Env":       "env",
+		"DryRun":    "dry-run",
+		"APIKey":    "api-key",
+		"ServerURL": "server-url",
+	}
+
+	for in, want := range tests {
+		if got := kebabCase(in); got != want {
+			t.Errorf("kebabCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
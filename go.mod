@@ -3,7 +3,7 @@ module github.com/gnemade360/go-cli
 go 1.24.2
 
 require (
-	github.com/gnemade360/go-config v0.1.3 // indirect
-	github.com/gnemade360/go-map-navigator v0.1.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	github.com/gnemade360/go-config v0.1.3
+	github.com/gnemade360/go-map-navigator v0.1.0
+	gopkg.in/yaml.v3 v3.0.1
 )
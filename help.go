@@ -0,0 +1,178 @@
+package gocli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const helpCommandName = "help"
+
+func (c *Command) CommandPath() string {
+	if c.parent != nil {
+		return c.parent.CommandPath() + " " + c.commandName
+	}
+	return c.commandName
+}
+
+func (c *Command) UseLine() string {
+	path := c.CommandPath()
+	if len(c.visibleFlags()) > 0 {
+		path += " [flags]"
+	}
+	if len(c.positionals) > 0 {
+		return path + " " + c.argsUsage()
+	}
+	if c.run != nil {
+		return path + " [args]"
+	}
+	return path
+}
+
+func (c *Command) HasSubCommands() bool {
+	return len(c.commands) > 0
+}
+
+func (c *Command) Commands() []*Command {
+	return c.commands
+}
+
+func (c *Command) Parent() *Command {
+	return c.parent
+}
+
+func (c *Command) HelpOutput() io.Writer {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.helpOutput != nil {
+			return cmd.helpOutput
+		}
+	}
+
+	return c.OutOrStdout()
+}
+
+func (c *Command) Help() error {
+	_, err := io.WriteString(c.HelpOutput(), c.HelpString())
+	return err
+}
+
+func (c *Command) HelpString() string {
+	var b strings.Builder
+
+	if desc := c.description(); desc != "" {
+		b.WriteString(strings.TrimRight(desc, "\n"))
+		b.WriteString("\n\n")
+	}
+
+	if c.deprecated != "" {
+		fmt.Fprintf(&b, "Deprecated: %s\n\n", c.deprecated)
+	}
+
+	b.WriteString("Usage:\n")
+	if c.run != nil || !c.HasSubCommands() {
+		fmt.Fprintf(&b, "  %s\n", c.UseLine())
+	}
+	if c.HasSubCommands() {
+		fmt.Fprintf(&b, "  %s [command]\n", c.CommandPath())
+	}
+
+	if aliases := c.visibleAliases(); len(aliases) > 0 {
+		b.WriteString("\nAliases:\n")
+		fmt.Fprintf(&b, "  %s\n", strings.Join(append([]string{c.commandName}, aliases...), ", "))
+	}
+
+	if commands := c.visibleCommands(); len(commands) > 0 {
+		b.WriteString("\nAvailable Commands:\n")
+		width := 0
+		for _, cmd := range commands {
+			if len(cmd.commandName) > width {
+				width = len(cmd.commandName)
+			}
+		}
+		for _, cmd := range commands {
+			fmt.Fprintf(&b, "  %-*s  %s\n", width, cmd.commandName, cmd.short)
+		}
+	}
+
+	b.WriteString("\nFlags:\n")
+	flags := append(c.LocalFlags(), Flag{
+		Name:      "help",
+		Shorthand: "h",
+		Usage:     "help for " + c.commandName,
+		Type:      Bool,
+	})
+	writeFlagUsages(&b, flags)
+
+	if inherited := c.InheritedFlags(); len(inherited) > 0 {
+		b.WriteString("\nGlobal Flags:\n")
+		writeFlagUsages(&b, inherited)
+	}
+
+	if c.HasSubCommands() {
+		fmt.Fprintf(&b, "\nUse \"%s [command] --help\" for more information about a command.\n", c.CommandPath())
+	}
+
+	return b.String()
+}
+
+func (c *Command) description() string {
+	if c.long != "" {
+		return c.long
+	}
+	return c.short
+}
+
+func writeFlagUsages(b *strings.Builder, flags []Flag) {
+	width := 0
+	for _, f := range flags {
+		if l := len(f.usageLine()); l > width {
+			width = l
+		}
+	}
+
+	for _, f := range flags {
+		fmt.Fprintf(b, "  %-*s   %s%s\n", width, f.usageLine(), f.Usage, f.defaultText())
+	}
+}
+
+// helpTarget resolves the command named by "help [command...]". Unlike
+// resolve, it fails instead of falling back to the nearest parent, so a typo
+// isn't answered with unrelated help.
+func (c *Command) helpTarget(names []string) (*Command, error) {
+	target := c
+	for _, name := range names {
+		if name == "--" || isFlagArg(name) {
+			break
+		}
+
+		cmd := target.findCommand(name)
+		if cmd == nil {
+			return nil, &UnknownCommandError{
+				Command:     target.CommandPath(),
+				Name:        name,
+				Suggestions: target.SuggestionsFor(name),
+			}
+		}
+		target = cmd
+	}
+
+	return target, nil
+}
+
+// hasHelpFlag skips the values of value-taking flags, so "--name -h" sets
+// name instead of asking for help.
+func (c *Command) hasHelpFlag(args []string) bool {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return false
+		}
+		if arg == "-h" || arg == "--help" {
+			return true
+		}
+		if isFlagArg(arg) && c.flagNeedsValue(arg) {
+			i++
+		}
+	}
+	return false
+}
@@ -0,0 +1,248 @@
+package gocli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gnemade360/go-config/configutil"
+)
+
+func TestCommand_CommandPath(t *testing.T) {
+	rootCmd := NewCommand(WithName("root"))
+	subCmd := NewCommand(WithName("sub"))
+	leafCmd := NewCommand(WithName("leaf"))
+
+	rootCmd.AddCommand(subCmd)
+	subCmd.AddCommand(leafCmd)
+
+	if got := leafCmd.CommandPath(); got != "root sub leaf" {
+		t.Errorf("expected path 'root sub leaf', got '%s'", got)
+	}
+}
+
+func TestCommand_HelpString(t *testing.T) {
+	var buf bytes.Buffer
+	executed := false
+	rootCmd := newToolboxTree(&executed, WithHelpOutput(&buf))
+
+	help := rootCmd.HelpString()
+
+	expected := []string{
+		"Toolbox demonstrates help generation.",
+		"Usage:\n  toolbox [command]",
+		"Available Commands:",
+		"  version     Print version information",
+		"  echo        Echo the provided arguments",
+		"  completion  Generate shell completion scripts",
+		"-h, --help",
+		`Use "toolbox [command] --help"`,
+	}
+
+	for _, want := range expected {
+		if !strings.Contains(help, want) {
+			t.Errorf("expected help to contain %q, got:\n%s", want, help)
+		}
+	}
+}
+
+func TestCommand_HelpFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"root long flag", []string{"--help"}, []string{"Available Commands:"}},
+		{"root short flag", []string{"-h"}, []string{"Available Commands:"}},
+		{"subcommand flag", []string{"version", "--help"}, []string{"Print version information", "toolbox version [flags] [args]", "Aliases:\n  version, v, ver"}},
+		{"alias flag", []string{"ver", "-h"}, []string{"toolbox version [flags] [args]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			executed := false
+			rootCmd := newToolboxTree(&executed, WithHelpOutput(&buf))
+
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			if executed {
+				t.Error("run should not execute when help is requested")
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestCommand_HelpFlagAfterTerminator(t *testing.T) {
+	var buf bytes.Buffer
+	executed := false
+	rootCmd := newToolboxTree(&executed, WithHelpOutput(&buf))
+
+	rootCmd.SetArgs([]string{"echo", "--", "--help"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if !executed {
+		t.Error("run should execute when --help follows --")
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no help output, got:\n%s", buf.String())
+	}
+}
+
+func TestCommand_HelpFlagAsFlagValue(t *testing.T) {
+	var buf bytes.Buffer
+	var name string
+
+	cmd := NewCommand(
+		WithName("app"),
+		WithHelpOutput(&buf),
+		WithFlag(StringFlag("name", "", "Name to greet")),
+		WithRun(func(cmd *Command, args []string) error {
+			name = configutil.GetString(cmd.Config(), "name", "")
+			return nil
+		}),
+	)
+
+	cmd.SetArgs([]string{"--name", "-h"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if name != "-h" {
+		t.Errorf("expected name '-h', got %q", name)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no help output, got:\n%s", buf.String())
+	}
+}
+
+func TestCommand_HelpSubcommand(t *testing.T) {
+	t.Run("root help", func(t *testing.T) {
+		var buf bytes.Buffer
+		executed := false
+		rootCmd := newToolboxTree(&executed, WithHelpOutput(&buf))
+
+		rootCmd.SetArgs([]string{"help"})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+
+		if !strings.Contains(buf.String(), "Available Commands:") {
+			t.Errorf("expected root help, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("help for subcommand", func(t *testing.T) {
+		var buf bytes.Buffer
+		executed := false
+		rootCmd := newToolboxTree(&executed, WithHelpOutput(&buf))
+
+		rootCmd.SetArgs([]string{"help", "v"})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+
+		if executed {
+			t.Error("run should not execute for help subcommand")
+		}
+
+		if !strings.Contains(buf.String(), "toolbox version [flags] [args]") {
+			t.Errorf("expected version help, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("help for unknown command", func(t *testing.T) {
+		var buf bytes.Buffer
+		executed := false
+		rootCmd := newToolboxTree(&executed, WithHelpOutput(&buf))
+
+		rootCmd.SetArgs([]string{"help", "ecoh"})
+
+		err := rootCmd.Execute()
+
+		var unknownErr *UnknownCommandError
+		if !errors.As(err, &unknownErr) {
+			t.Fatalf("expected UnknownCommandError, got %v", err)
+		}
+
+		if unknownErr.Name != "ecoh" || len(unknownErr.Suggestions) != 1 || unknownErr.Suggestions[0] != "echo" {
+			t.Errorf("expected a suggestion for echo, got %+v", unknownErr)
+		}
+
+		if buf.Len() != 0 {
+			t.Errorf("expected no help output, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("no subcommands takes help as an argument", func(t *testing.T) {
+		var buf bytes.Buffer
+		var gotArgs []string
+
+		cmd := NewCommand(
+			WithName("greet"),
+			WithHelpOutput(&buf),
+			WithRun(func(cmd *Command, args []string) error {
+				gotArgs = args
+				return nil
+			}),
+		)
+
+		cmd.SetArgs([]string{"help"})
+
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+
+		if strings.Join(gotArgs, ",") != "help" {
+			t.Errorf("expected args [help], got %v", gotArgs)
+		}
+
+		if buf.Len() != 0 {
+			t.Errorf("expected no help output, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("user defined help command wins", func(t *testing.T) {
+		var buf bytes.Buffer
+		executed := false
+		rootCmd := newToolboxTree(&executed, WithHelpOutput(&buf))
+
+		customExecuted := false
+		rootCmd.AddCommand(NewCommand(
+			WithName("help"),
+			WithRun(func(cmd *Command, args []string) error {
+				customExecuted = true
+				return nil
+			}),
+		))
+
+		rootCmd.SetArgs([]string{"help"})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+
+		if !customExecuted {
+			t.Error("user defined help command should execute")
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package gocli
+
+import "fmt"
+
+type HookPolicy int
+
+const (
+	hookPolicyUnset HookPolicy = iota
+	HooksAll
+	HooksNearest
+)
+
+func (c *Command) HookPolicy() HookPolicy {
+	if c.hookPolicy != hookPolicyUnset {
+		return c.hookPolicy
+	}
+
+	if c.parent != nil {
+		return c.parent.HookPolicy()
+	}
+
+	return HooksAll
+}
+
+// persistentHooks returns the hooks declared on c and its ancestors, ordered
+// root first. Under HooksNearest only the hook closest to c is kept.
+func (c *Command) persistentHooks(hook func(*Command) CommandFunc) []CommandFunc {
+	hooks := make([]CommandFunc, 0)
+
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		fn := hook(cmd)
+		if fn == nil {
+			continue
+		}
+
+		if c.HookPolicy() == HooksNearest {
+			return []CommandFunc{fn}
+		}
+
+		hooks = append([]CommandFunc{fn}, hooks...)
+	}
+
+	return hooks
+}
+
+func (c *Command) runPersistentPreRuns(args []string) error {
+	hooks := c.persistentHooks(func(cmd *Command) CommandFunc { return cmd.persistentPreRun })
+
+	for _, hook := range hooks {
+		if err := hook(c, args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Command) runPersistentPostRuns(args []string) error {
+	hooks := c.persistentHooks(func(cmd *Command) CommandFunc { return cmd.persistentPostRun })
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i](c, args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handleError passes err through the OnError hooks from c up to the root.
+// Each hook sees the error returned by the previous one, and returning nil
+// swallows the error.
+func (c *Command) handleError(args []string, err error) error {
+	for cmd := c; cmd != nil && err != nil; cmd = cmd.parent {
+		if cmd.onError != nil {
+			err = cmd.onError(c, args, err)
+		}
+	}
+
+	return err
+}
+
+func (c *Command) handleSuccess(args []string) error {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.onSuccess == nil {
+			continue
+		}
+
+		if err := cmd.onSuccess(c, args); err != nil {
+			return fmt.Errorf("onSuccess failed: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,364 @@
+package gocli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCommand_PersistentHooksRunAlongPath(t *testing.T) {
+	var order []string
+	record := func(name string) CommandFunc {
+		return func(cmd *Command, args []string) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	rootCmd := NewCommand(
+		WithName("root"),
+		WithPersistentPreRun(record("root.persistentPreRun")),
+		WithPersistentPostRun(record("root.persistentPostRun")),
+	)
+	groupCmd := NewCommand(
+		WithName("group"),
+		WithPersistentPreRun(record("group.persistentPreRun")),
+		WithPersistentPostRun(record("group.persistentPostRun")),
+	)
+	groupCmd.AddCommand(NewCommand(
+		WithName("leaf"),
+		WithPreRun(record("leaf.preRun")),
+		WithRun(record("leaf.run")),
+		WithPostRun(record("leaf.postRun")),
+	))
+	rootCmd.AddCommand(groupCmd)
+
+	rootCmd.SetArgs([]string{"group", "leaf"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	expected := []string{
+		"root.persistentPreRun",
+		"group.persistentPreRun",
+		"leaf.preRun",
+		"leaf.run",
+		"leaf.postRun",
+		"group.persistentPostRun",
+		"root.persistentPostRun",
+	}
+
+	if strings.Join(order, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected order %v, got %v", expected, order)
+	}
+}
+
+func TestCommand_PersistentHooksNearestPolicy(t *testing.T) {
+	t.Run("nearest ancestor only", func(t *testing.T) {
+		var order []string
+		record := func(name string) CommandFunc {
+			return func(cmd *Command, args []string) error {
+				order = append(order, name)
+				return nil
+			}
+		}
+
+		rootCmd := NewCommand(
+			WithName("root"),
+			WithHookPolicy(HooksNearest),
+			WithPersistentPreRun(record("root.persistentPreRun")),
+			WithPersistentPostRun(record("root.persistentPostRun")),
+		)
+		groupCmd := NewCommand(
+			WithName("group"),
+			WithPersistentPreRun(record("group.persistentPreRun")),
+			WithPersistentPostRun(record("group.persistentPostRun")),
+		)
+		groupCmd.AddCommand(NewCommand(
+			WithName("leaf"),
+			WithPreRun(record("leaf.preRun")),
+			WithRun(record("leaf.run")),
+			WithPostRun(record("leaf.postRun")),
+		))
+		rootCmd.AddCommand(groupCmd)
+
+		rootCmd.SetArgs([]string{"group", "leaf"})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+
+		expected := []string{
+			"group.persistentPreRun",
+			"leaf.preRun",
+			"leaf.run",
+			"leaf.postRun",
+			"group.persistentPostRun",
+		}
+
+		if strings.Join(order, ",") != strings.Join(expected, ",") {
+			t.Errorf("expected order %v, got %v", expected, order)
+		}
+	})
+
+	t.Run("policy set closer to the target wins", func(t *testing.T) {
+		hookCount := 0
+		count := func(cmd *Command, args []string) error {
+			hookCount++
+			return nil
+		}
+
+		rootCmd := NewCommand(
+			WithName("root"),
+			WithHookPolicy(HooksNearest),
+			WithPersistentPreRun(count),
+			WithPersistentPostRun(count),
+		)
+		groupCmd := NewCommand(
+			WithName("group"),
+			WithHookPolicy(HooksAll),
+			WithPersistentPreRun(count),
+			WithPersistentPostRun(count),
+		)
+		groupCmd.AddCommand(NewCommand(
+			WithName("leaf"),
+			WithRun(func(cmd *Command, args []string) error { return nil }),
+		))
+		rootCmd.AddCommand(groupCmd)
+
+		rootCmd.SetArgs([]string{"group", "leaf"})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+
+		if hookCount != 4 {
+			t.Errorf("expected every persistent hook to run, got %d", hookCount)
+		}
+	})
+}
+
+func TestCommand_PersistentHooksReceiveTarget(t *testing.T) {
+	var received string
+
+	rootCmd := NewCommand(
+		WithName("root"),
+		WithPersistentPreRun(func(cmd *Command, args []string) error {
+			received = cmd.CommandPath()
+			return nil
+		}),
+	)
+	rootCmd.AddCommand(NewCommand(WithName("sub"), WithRun(func(cmd *Command, args []string) error { return nil })))
+
+	rootCmd.SetArgs([]string{"sub"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if received != "root sub" {
+		t.Errorf("expected hook to receive the target command, got '%s'", received)
+	}
+}
+
+func TestCommand_PersistentPreRunError(t *testing.T) {
+	testError := errors.New("not authenticated")
+	runExecuted := false
+
+	rootCmd := NewCommand(
+		WithName("root"),
+		WithPersistentPreRun(func(cmd *Command, args []string) error {
+			return testError
+		}),
+	)
+	rootCmd.AddCommand(NewCommand(
+		WithName("sub"),
+		WithRun(func(cmd *Command, args []string) error {
+			runExecuted = true
+			return nil
+		}),
+	))
+
+	rootCmd.SetArgs([]string{"sub"})
+
+	err := rootCmd.Execute()
+	if !errors.Is(err, testError) {
+		t.Fatalf("expected persistent hook error, got %v", err)
+	}
+
+	if runExecuted {
+		t.Error("run should not execute after a persistent PreRun error")
+	}
+}
+
+func TestCommand_OnErrorRewritesError(t *testing.T) {
+	networkError := errors.New("dial tcp: connection refused")
+
+	rootCmd := NewCommand(
+		WithName("root"),
+		WithOnError(func(cmd *Command, args []string, err error) error {
+			if errors.Is(err, networkError) {
+				return fmt.Errorf("could not reach the server, check your network connection: %w", err)
+			}
+			return err
+		}),
+	)
+	rootCmd.AddCommand(NewCommand(
+		WithName("sync"),
+		WithRun(func(cmd *Command, args []string) error {
+			return networkError
+		}),
+	))
+
+	rootCmd.SetArgs([]string{"sync"})
+
+	err := rootCmd.Execute()
+	if err == nil || !strings.HasPrefix(err.Error(), "could not reach the server") {
+		t.Errorf("expected rewritten error, got %v", err)
+	}
+
+	if !errors.Is(err, networkError) {
+		t.Error("rewritten error should wrap the original")
+	}
+}
+
+func TestCommand_OnErrorChainsToAncestors(t *testing.T) {
+	var order []string
+
+	rootCmd := NewCommand(
+		WithName("root"),
+		WithOnError(func(cmd *Command, args []string, err error) error {
+			order = append(order, "root:"+err.Error())
+			return err
+		}),
+	)
+	rootCmd.AddCommand(NewCommand(
+		WithName("sub"),
+		WithRun(func(cmd *Command, args []string) error {
+			return errors.New("failed")
+		}),
+		WithOnError(func(cmd *Command, args []string, err error) error {
+			order = append(order, "sub")
+			return errors.New("translated")
+		}),
+	))
+
+	rootCmd.SetArgs([]string{"sub"})
+
+	err := rootCmd.Execute()
+	if err == nil || err.Error() != "translated" {
+		t.Errorf("expected translated error, got %v", err)
+	}
+
+	if strings.Join(order, ",") != "sub,root:translated" {
+		t.Errorf("expected hooks from target to root, got %v", order)
+	}
+}
+
+func TestCommand_OnErrorSwallowsError(t *testing.T) {
+	rootHookExecuted := false
+
+	rootCmd := NewCommand(
+		WithName("root"),
+		WithOnError(func(cmd *Command, args []string, err error) error {
+			rootHookExecuted = true
+			return err
+		}),
+	)
+	rootCmd.AddCommand(NewCommand(
+		WithName("sub"),
+		WithRun(func(cmd *Command, args []string) error {
+			return errors.New("ignorable")
+		}),
+		WithOnError(func(cmd *Command, args []string, err error) error {
+			return nil
+		}),
+	))
+
+	rootCmd.SetArgs([]string{"sub"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Errorf("expected error to be swallowed, got %v", err)
+	}
+
+	if rootHookExecuted {
+		t.Error("ancestor OnError should not run once the error is swallowed")
+	}
+}
+
+func TestCommand_OnSuccess(t *testing.T) {
+	var order []string
+	errorHookExecuted := false
+
+	rootCmd := NewCommand(
+		WithName("root"),
+		WithOnSuccess(func(cmd *Command, args []string) error {
+			order = append(order, "root")
+			return nil
+		}),
+		WithOnError(func(cmd *Command, args []string, err error) error {
+			errorHookExecuted = true
+			return err
+		}),
+	)
+	rootCmd.AddCommand(NewCommand(
+		WithName("sub"),
+		WithRun(func(cmd *Command, args []string) error {
+			order = append(order, "run")
+			return nil
+		}),
+		WithFinally(func(cmd *Command, args []string) error {
+			order = append(order, "finally")
+			return nil
+		}),
+		WithOnSuccess(func(cmd *Command, args []string) error {
+			order = append(order, "sub")
+			return nil
+		}),
+	))
+
+	rootCmd.SetArgs([]string{"sub"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if strings.Join(order, ",") != "run,finally,sub,root" {
+		t.Errorf("expected OnSuccess after finalizers from target to root, got %v", order)
+	}
+
+	if errorHookExecuted {
+		t.Error("OnError should not run on success")
+	}
+}
+
+func TestCommand_OnErrorSeesFinalizerErrors(t *testing.T) {
+	cleanupError := errors.New("cleanup error")
+	var received error
+
+	cmd := NewCommand(
+		WithName("test"),
+		WithRun(func(cmd *Command, args []string) error {
+			return nil
+		}),
+		WithFinally(func(cmd *Command, args []string) error {
+			return cleanupError
+		}),
+		WithOnError(func(cmd *Command, args []string, err error) error {
+			received = err
+			return err
+		}),
+	)
+
+	cmd.SetArgs([]string{})
+
+	if err := cmd.Execute(); !errors.Is(err, cleanupError) {
+		t.Errorf("expected cleanup error, got %v", err)
+	}
+
+	if !errors.Is(received, cleanupError) {
+		t.Errorf("OnError should receive finalizer errors, got %v", received)
+	}
+}
@@ -0,0 +1,133 @@
+package gocli
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+const maxInvokeDepth = 32
+
+// Invocation holds the state of a single execution. The command tree itself
+// is never modified while executing: the commands on the resolved path are
+// bound copies that refer to the invocation, so one tree can serve many
+// concurrent executions. An Invocation can be executed again once the previous
+// execution has returned, but it must not be shared by concurrent executions.
+type Invocation struct {
+	Args   []string
+	In     io.Reader
+	Out    io.Writer
+	ErrOut io.Writer
+
+	ctx        context.Context
+	path       []*Command
+	flagValues map[string]interface{}
+	depth      int
+
+	mu       sync.Mutex
+	deferred []func() error
+}
+
+func (inv *Invocation) Context() context.Context {
+	if inv.ctx == nil {
+		return context.Background()
+	}
+	return inv.ctx
+}
+
+func (inv *Invocation) Path() []*Command {
+	return inv.path
+}
+
+func (inv *Invocation) Target() *Command {
+	if len(inv.path) == 0 {
+		return nil
+	}
+	return inv.path[len(inv.path)-1]
+}
+
+func (inv *Invocation) FlagValues() map[string]interface{} {
+	values := make(map[string]interface{}, len(inv.flagValues))
+	for name, value := range inv.flagValues {
+		values[name] = value
+	}
+	return values
+}
+
+// reset clears the state left behind by a previous execution of inv.
+func (inv *Invocation) reset(ctx context.Context) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	inv.ctx = ctx
+	inv.path = nil
+	inv.flagValues = nil
+	inv.deferred = nil
+}
+
+func (inv *Invocation) addDeferred(fn func() error) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	inv.deferred = append(inv.deferred, fn)
+}
+
+func (inv *Invocation) takeDeferred() []func() error {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	deferred := inv.deferred
+	inv.deferred = nil
+	return deferred
+}
+
+func (c *Command) Invocation() *Invocation {
+	return c.inv
+}
+
+// Invoke executes the command found by resolving args below c, running its
+// full lifecycle. When called during an execution the new invocation inherits
+// the current streams, and nesting is limited to catch commands that invoke
+// each other in a cycle.
+func (c *Command) Invoke(ctx context.Context, args ...string) error {
+	depth := 0
+	if c.inv != nil {
+		depth = c.inv.depth + 1
+	}
+
+	if depth > maxInvokeDepth {
+		return &InvokeDepthError{Depth: maxInvokeDepth, Args: args}
+	}
+
+	return c.ExecuteInvocation(ctx, &Invocation{
+		Args:   args,
+		In:     c.InOrStdin(),
+		Out:    c.OutOrStdout(),
+		ErrOut: c.ErrOrStderr(),
+		depth:  depth,
+	})
+}
+
+func (c *Command) bind(inv *Invocation) *Command {
+	return c.bindBelow(nil, nil, inv)
+}
+
+// bindBelow copies c and its ancestors for inv. The copy of ancestor is
+// reused when the walk reaches it, so a path can be bound in two steps.
+func (c *Command) bindBelow(ancestor, boundAncestor *Command, inv *Invocation) *Command {
+	if c == ancestor {
+		return boundAncestor
+	}
+
+	var parent *Command
+	if c.parent != nil {
+		parent = c.parent.bindBelow(ancestor, boundAncestor, inv)
+	}
+
+	bound := *c
+	bound.parent = parent
+	bound.inv = inv
+	bound.ctx = nil
+	bound.flagValues = nil
+	bound.argValues = nil
+	bound.configValues = nil
+	bound.activeTimeout = nil
+	return &bound
+}
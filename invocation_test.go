@@ -0,0 +1,340 @@
+package gocli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gnemade360/go-config/configutil"
+)
+
+type invocationKey struct{}
+
+func TestCommand_ConcurrentInvocations(t *testing.T) {
+	rootCmd := NewCommand(
+		WithName("gateway"),
+		WithPersistentFlag(StringFlag("user", "", "User name")),
+		WithPersistentPreRun(func(cmd *Command, args []string) error {
+			user := configutil.GetString(cmd.Config(), "user", "")
+			cmd.WithContextValue(invocationKey{}, user)
+			return nil
+		}),
+	)
+
+	rootCmd.AddCommand(NewCommand(
+		WithName("echo"),
+		WithRun(func(cmd *Command, args []string) error {
+			cmd.Defer(func() error {
+				fmt.Fprint(cmd.ErrOrStderr(), "closed")
+				return nil
+			})
+			user := cmd.Context().Value(invocationKey{})
+			fmt.Fprintf(cmd.OutOrStdout(), "%v:%s", user, strings.Join(args, " "))
+			return nil
+		}),
+	))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			var out, errOut bytes.Buffer
+			user := fmt.Sprintf("user%d", i)
+			inv := &Invocation{
+				Args:   []string{"echo", "--user", user, fmt.Sprint(i)},
+				Out:    &out,
+				ErrOut: &errOut,
+			}
+
+			if err := rootCmd.ExecuteInvocation(context.Background(), inv); err != nil {
+				t.Errorf("ExecuteInvocation failed: %v", err)
+				return
+			}
+
+			if want := fmt.Sprintf("%s:%d", user, i); out.String() != want {
+				t.Errorf("expected %q, got %q", want, out.String())
+			}
+
+			if errOut.String() != "closed" {
+				t.Errorf("expected deferred output, got %q", errOut.String())
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestCommand_InvocationState(t *testing.T) {
+	var inv *Invocation
+
+	rootCmd := NewCommand(WithName("root"))
+	subCmd := NewCommand(
+		WithName("sub"),
+		WithFlag(IntFlag("count", 1, "Count")),
+		WithRun(func(cmd *Command, args []string) error {
+			inv = cmd.Invocation()
+			return nil
+		}),
+	)
+	rootCmd.AddCommand(subCmd)
+
+	rootCmd.SetArgs([]string{"sub", "--count", "3", "arg"})
+
+	ctx := context.WithValue(context.Background(), invocationKey{}, "value")
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		t.Fatalf("ExecuteContext failed: %v", err)
+	}
+
+	if inv == nil {
+		t.Fatal("expected the command to be bound to an invocation")
+	}
+
+	if inv.Context().Value(invocationKey{}) != "value" {
+		t.Error("invocation did not keep the context")
+	}
+
+	if strings.Join(inv.Args, " ") != "sub --count 3 arg" {
+		t.Errorf("unexpected invocation args %v", inv.Args)
+	}
+
+	path := make([]string, 0, len(inv.Path()))
+	for _, cmd := range inv.Path() {
+		path = append(path, cmd.Name())
+	}
+	if strings.Join(path, " ") != "root sub" {
+		t.Errorf("expected resolved path 'root sub', got %v", path)
+	}
+
+	if inv.Target().Name() != "sub" {
+		t.Errorf("expected target 'sub', got '%s'", inv.Target().Name())
+	}
+
+	if inv.FlagValues()["count"] != 3 {
+		t.Errorf("expected parsed count=3, got %v", inv.FlagValues()["count"])
+	}
+
+	if subCmd.FlagChanged("count") || subCmd.Invocation() != nil {
+		t.Error("the command tree should not be modified by an execution")
+	}
+}
+
+func TestCommand_InvocationReused(t *testing.T) {
+	rootCmd := NewCommand(WithName("root"))
+	rootCmd.AddCommand(NewCommand(
+		WithName("sub"),
+		WithFlag(IntFlag("count", 1, "Count")),
+		WithRun(func(cmd *Command, args []string) error { return nil }),
+	))
+
+	inv := &Invocation{Args: []string{"sub", "--count", "3"}}
+	ctx := context.WithValue(context.Background(), invocationKey{}, "value")
+	if err := rootCmd.ExecuteInvocation(ctx, inv); err != nil {
+		t.Fatalf("first ExecuteInvocation failed: %v", err)
+	}
+
+	inv.Args = []string{"sub"}
+	if err := rootCmd.ExecuteInvocation(context.Background(), inv); err != nil {
+		t.Fatalf("second ExecuteInvocation failed: %v", err)
+	}
+
+	if len(inv.Path()) != 2 {
+		t.Errorf("expected path of 2 commands, got %d", len(inv.Path()))
+	}
+
+	if inv.Context().Value(invocationKey{}) != nil {
+		t.Error("context from the previous execution should not carry over")
+	}
+
+	if value, ok := inv.FlagValues()["count"]; ok {
+		t.Errorf("flag values from the previous execution should not carry over, got count=%v", value)
+	}
+}
+
+func TestCommand_InvocationStreamsOverrideTree(t *testing.T) {
+	var treeOut, invOut bytes.Buffer
+
+	cmd := NewCommand(
+		WithName("test"),
+		WithRun(func(cmd *Command, args []string) error {
+			fmt.Fprint(cmd.OutOrStdout(), "hello")
+			return nil
+		}),
+	)
+	cmd.SetOut(&treeOut)
+
+	if err := cmd.ExecuteInvocation(context.Background(), &Invocation{Out: &invOut}); err != nil {
+		t.Fatalf("ExecuteInvocation failed: %v", err)
+	}
+
+	if invOut.String() != "hello" || treeOut.Len() != 0 {
+		t.Errorf("expected output on the invocation stream, got invocation=%q tree=%q", invOut.String(), treeOut.String())
+	}
+}
+
+func TestCommand_Invoke(t *testing.T) {
+	var order []string
+	var out bytes.Buffer
+
+	rootCmd := NewCommand(
+		WithName("app"),
+		WithPersistentPreRun(func(cmd *Command, args []string) error {
+			order = append(order, "auth:"+cmd.Name())
+			return nil
+		}),
+	)
+	rootCmd.AddCommand(
+		NewCommand(
+			WithName("build"),
+			WithFlag(StringFlag("target", "dev", "Build target")),
+			WithRun(func(cmd *Command, args []string) error {
+				target := configutil.GetString(cmd.Config(), "target", "")
+				order = append(order, "build:"+target)
+				fmt.Fprintf(cmd.OutOrStdout(), "built %s\n", target)
+				return nil
+			}),
+		),
+		NewCommand(
+			WithName("migrate"),
+			WithArgValidator(ExactArgs(1)),
+			WithRun(func(cmd *Command, args []string) error {
+				order = append(order, "migrate:"+args[0])
+				return nil
+			}),
+		),
+		NewCommand(
+			WithName("deploy"),
+			WithRun(func(cmd *Command, args []string) error {
+				if err := cmd.Root().Invoke(cmd.Context(), "build", "--target", "prod"); err != nil {
+					return err
+				}
+				if err := cmd.Root().Invoke(cmd.Context(), append([]string{"migrate"}, args...)...); err != nil {
+					return err
+				}
+				order = append(order, "deploy")
+				return nil
+			}),
+		),
+	)
+
+	rootCmd.SetArgs([]string{"deploy", "v2"})
+	rootCmd.SetOut(&out)
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	expected := []string{"auth:deploy", "auth:build", "build:prod", "auth:migrate", "migrate:v2", "deploy"}
+	if strings.Join(order, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected order %v, got %v", expected, order)
+	}
+
+	if out.String() != "built prod\n" {
+		t.Errorf("expected invoked command to write to the inherited stream, got %q", out.String())
+	}
+}
+
+func TestCommand_InvokeReturnsValidationError(t *testing.T) {
+	deployed := false
+
+	rootCmd := NewCommand(WithName("app"))
+	rootCmd.AddCommand(
+		NewCommand(
+			WithName("migrate"),
+			WithArgValidator(ExactArgs(1)),
+			WithRun(func(cmd *Command, args []string) error { return nil }),
+		),
+		NewCommand(
+			WithName("deploy"),
+			WithRun(func(cmd *Command, args []string) error {
+				if err := cmd.Root().Invoke(cmd.Context(), append([]string{"migrate"}, args...)...); err != nil {
+					return err
+				}
+				deployed = true
+				return nil
+			}),
+		),
+	)
+
+	rootCmd.SetArgs([]string{"deploy"})
+
+	err := rootCmd.Execute()
+
+	var argsErr *InvalidArgsError
+	if !errors.As(err, &argsErr) {
+		t.Fatalf("expected InvalidArgsError from the invoked command, got %v", err)
+	}
+
+	if deployed {
+		t.Error("deploy should stop when an invoked command fails")
+	}
+}
+
+func TestCommand_InvokeInheritsInvocationStreams(t *testing.T) {
+	var out bytes.Buffer
+
+	rootCmd := NewCommand(WithName("app"))
+	rootCmd.AddCommand(
+		NewCommand(
+			WithName("build"),
+			WithRun(func(cmd *Command, args []string) error {
+				fmt.Fprintln(cmd.OutOrStdout(), "built prod")
+				return nil
+			}),
+		),
+		NewCommand(
+			WithName("deploy"),
+			WithRun(func(cmd *Command, args []string) error {
+				return cmd.Root().Invoke(cmd.Context(), "build")
+			}),
+		),
+	)
+
+	inv := &Invocation{Args: []string{"deploy", "v2"}, Out: &out}
+	if err := rootCmd.ExecuteInvocation(context.Background(), inv); err != nil {
+		t.Fatalf("ExecuteInvocation failed: %v", err)
+	}
+
+	if out.String() != "built prod\n" {
+		t.Errorf("expected output on the invocation stream, got %q", out.String())
+	}
+}
+
+func TestCommand_InvokeDepthLimit(t *testing.T) {
+	calls := 0
+
+	rootCmd := NewCommand(WithName("app"))
+	rootCmd.AddCommand(
+		NewCommand(
+			WithName("ping"),
+			WithRun(func(cmd *Command, args []string) error {
+				calls++
+				return cmd.Root().Invoke(cmd.Context(), "pong")
+			}),
+		),
+		NewCommand(
+			WithName("pong"),
+			WithRun(func(cmd *Command, args []string) error {
+				calls++
+				return cmd.Root().Invoke(cmd.Context(), "ping")
+			}),
+		),
+	)
+
+	rootCmd.SetArgs([]string{"ping"})
+
+	err := rootCmd.Execute()
+
+	var depthErr *InvokeDepthError
+	if !errors.As(err, &depthErr) {
+		t.Fatalf("expected InvokeDepthError, got %v", err)
+	}
+
+	if calls != maxInvokeDepth+1 {
+		t.Errorf("expected %d calls before the limit, got %d", maxInvokeDepth+1, calls)
+	}
+}
@@ -0,0 +1,15 @@
+package gocli
+
+type Middleware func(next CommandFunc) CommandFunc
+
+// applyMiddleware wraps fn with the middleware declared on c and its
+// ancestors. The root's middleware is the outermost layer.
+func (c *Command) applyMiddleware(fn CommandFunc) CommandFunc {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		for i := len(cmd.middleware) - 1; i >= 0; i-- {
+			fn = cmd.middleware[i](fn)
+		}
+	}
+
+	return fn
+}
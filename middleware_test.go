@@ -0,0 +1,170 @@
+package gocli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCommand_MiddlewareOrder(t *testing.T) {
+	var order []string
+
+	trace := func(name string) Middleware {
+		return func(next CommandFunc) CommandFunc {
+			return func(cmd *Command, args []string) error {
+				order = append(order, name+":before")
+				err := next(cmd, args)
+				order = append(order, name+":after")
+				return err
+			}
+		}
+	}
+
+	rootCmd := NewCommand(
+		WithName("root"),
+		WithMiddleware(trace("root1"), trace("root2")),
+	)
+	subCmd := NewCommand(
+		WithName("sub"),
+		WithMiddleware(trace("sub")),
+		WithPreRun(func(cmd *Command, args []string) error {
+			order = append(order, "preRun")
+			return nil
+		}),
+		WithRun(func(cmd *Command, args []string) error {
+			order = append(order, "run")
+			return nil
+		}),
+		WithPostRun(func(cmd *Command, args []string) error {
+			order = append(order, "postRun")
+			return nil
+		}),
+		WithFinally(func(cmd *Command, args []string) error {
+			order = append(order, "finally")
+			return nil
+		}),
+	)
+	rootCmd.AddCommand(subCmd)
+
+	rootCmd.SetArgs([]string{"sub"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	expected := []string{
+		"root1:before", "root2:before", "sub:before",
+		"preRun", "run", "postRun",
+		"sub:after", "root2:after", "root1:after",
+		"finally",
+	}
+
+	if strings.Join(order, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected order %v, got %v", expected, order)
+	}
+}
+
+func TestCommand_MiddlewareShortCircuit(t *testing.T) {
+	errUnauthorized := errors.New("unauthorized")
+	runExecuted := false
+
+	requireAuth := func(next CommandFunc) CommandFunc {
+		return func(cmd *Command, args []string) error {
+			return errUnauthorized
+		}
+	}
+
+	rootCmd := NewCommand(WithName("root"), WithMiddleware(requireAuth))
+	rootCmd.AddCommand(NewCommand(
+		WithName("deploy"),
+		WithRun(func(cmd *Command, args []string) error {
+			runExecuted = true
+			return nil
+		}),
+	))
+
+	rootCmd.SetArgs([]string{"deploy"})
+
+	if err := rootCmd.Execute(); !errors.Is(err, errUnauthorized) {
+		t.Errorf("expected unauthorized error, got %v", err)
+	}
+
+	if runExecuted {
+		t.Error("run should not execute when middleware short-circuits")
+	}
+}
+
+func TestCommand_MiddlewareRetry(t *testing.T) {
+	attempts := 0
+
+	retry := func(next CommandFunc) CommandFunc {
+		return func(cmd *Command, args []string) error {
+			var err error
+			for i := 0; i < 3; i++ {
+				if err = next(cmd, args); err == nil {
+					return nil
+				}
+			}
+			return err
+		}
+	}
+
+	cmd := NewCommand(
+		WithName("test"),
+		WithMiddleware(retry),
+		WithRun(func(cmd *Command, args []string) error {
+			attempts++
+			if attempts < 3 {
+				return fmt.Errorf("attempt %d failed", attempts)
+			}
+			return nil
+		}),
+	)
+
+	cmd.SetArgs([]string{})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestCommand_MiddlewareReceivesTargetAndArgs(t *testing.T) {
+	var path string
+	var received []string
+
+	rootCmd := NewCommand(
+		WithName("root"),
+		WithMiddleware(func(next CommandFunc) CommandFunc {
+			return func(cmd *Command, args []string) error {
+				path = cmd.CommandPath()
+				return next(cmd, append(args, "injected"))
+			}
+		}),
+	)
+	rootCmd.AddCommand(NewCommand(
+		WithName("sub"),
+		WithRun(func(cmd *Command, args []string) error {
+			received = args
+			return nil
+		}),
+	))
+
+	rootCmd.SetArgs([]string{"sub", "one"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if path != "root sub" {
+		t.Errorf("expected middleware to receive the target, got '%s'", path)
+	}
+
+	if strings.Join(received, ",") != "one,injected" {
+		t.Errorf("expected args rewritten by middleware, got %v", received)
+	}
+}
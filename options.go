@@ -1,6 +1,11 @@
 package gocli
 
-import "github.com/gnemade360/go-config/configprovider"
+import (
+	"io"
+	"time"
+
+	"github.com/gnemade360/go-config/configprovider"
+)
 
 type CommandOption func(*Command)
 
@@ -12,7 +17,11 @@ func WithName(name string) CommandOption {
 
 func WithAlias(aliases ...string) CommandOption {
 	return func(c *Command) {
-		c.aliases = aliases
+		for _, alias := range aliases {
+			if !contains(c.aliases, alias) {
+				c.aliases = append(c.aliases, alias)
+			}
+		}
 	}
 }
 
@@ -46,6 +55,99 @@ func WithPostRun(postRun CommandFunc) CommandOption {
 	}
 }
 
+func WithFinally(finally CommandFunc) CommandOption {
+	return func(c *Command) {
+		c.finally = finally
+	}
+}
+
+// WithPostRunOnFailure makes postRun and the persistent post-run hooks execute
+// even when run returns an error. Their errors are joined with the run error.
+func WithPostRunOnFailure() CommandOption {
+	return func(c *Command) {
+		c.postRunOnFailure = true
+	}
+}
+
+func WithOnError(onError ErrorFunc) CommandOption {
+	return func(c *Command) {
+		c.onError = onError
+	}
+}
+
+func WithOnSuccess(onSuccess CommandFunc) CommandOption {
+	return func(c *Command) {
+		c.onSuccess = onSuccess
+	}
+}
+
+func WithMiddleware(middleware ...Middleware) CommandOption {
+	return func(c *Command) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+func WithPersistentPreRun(preRun CommandFunc) CommandOption {
+	return func(c *Command) {
+		c.persistentPreRun = preRun
+	}
+}
+
+func WithPersistentPostRun(postRun CommandFunc) CommandOption {
+	return func(c *Command) {
+		c.persistentPostRun = postRun
+	}
+}
+
+func WithHookPolicy(policy HookPolicy) CommandOption {
+	return func(c *Command) {
+		c.hookPolicy = policy
+	}
+}
+
+func WithPanicRecovery() CommandOption {
+	return func(c *Command) {
+		c.panicRecovery = true
+	}
+}
+
+func WithCrashReportDir(dir string) CommandOption {
+	return func(c *Command) {
+		c.crashReportDir = dir
+	}
+}
+
+func WithSignalHandling(grace time.Duration) CommandOption {
+	return func(c *Command) {
+		c.signalHandling = true
+		c.signalGrace = grace
+	}
+}
+
+func WithTimeout(timeout time.Duration) CommandOption {
+	return func(c *Command) {
+		c.timeout = timeout
+	}
+}
+
+func WithTimeoutKey(key string) CommandOption {
+	return func(c *Command) {
+		c.timeoutKey = key
+	}
+}
+
+func WithDebug() CommandOption {
+	return func(c *Command) {
+		c.debug = true
+	}
+}
+
+func WithArgs(args ...Arg) CommandOption {
+	return func(c *Command) {
+		c.positionals = append(c.positionals, args...)
+	}
+}
+
 func WithArgValidator(validator ArgsValidator) CommandOption {
 	return func(c *Command) {
 		c.argValidation = validator
@@ -58,8 +160,108 @@ func WithAllowedArgs(args ...string) CommandOption {
 	}
 }
 
+// WithOnlyValidArgs rejects any argument missing from the allowed args. The
+// check runs before the validator set with WithArgValidator, and Validate
+// reports a command that uses it without any allowed args.
+func WithOnlyValidArgs() CommandOption {
+	return func(c *Command) {
+		c.onlyValidArgs = true
+	}
+}
+
+func WithArgCompletion(fn ArgCompletionFunc) CommandOption {
+	return func(c *Command) {
+		c.argCompletion = fn
+	}
+}
+
 func WithConfigProvider(provider configprovider.Provider) CommandOption {
 	return func(c *Command) {
 		c.configProvider = provider
 	}
 }
+
+func WithConfigKey(keys ...ConfigKey) CommandOption {
+	return func(c *Command) {
+		c.configKeys = append(c.configKeys, keys...)
+	}
+}
+
+func WithRequiredConfig(keys ...string) CommandOption {
+	return func(c *Command) {
+		c.requiredConfig = append(c.requiredConfig, keys...)
+	}
+}
+
+func WithConfigFile(path string) CommandOption {
+	return func(c *Command) {
+		c.configFile = path
+	}
+}
+
+func WithConfigExplain() CommandOption {
+	return func(c *Command) {
+		c.configExplain = true
+		c.persistentFlags = append(c.persistentFlags, BoolFlag(explainConfigFlag, false, "Explain where each config value comes from"))
+	}
+}
+
+func WithHelpOutput(w io.Writer) CommandOption {
+	return func(c *Command) {
+		c.helpOutput = w
+	}
+}
+
+func WithFlag(flags ...Flag) CommandOption {
+	return func(c *Command) {
+		c.flags = append(c.flags, flags...)
+	}
+}
+
+func WithPersistentFlag(flags ...Flag) CommandOption {
+	return func(c *Command) {
+		c.persistentFlags = append(c.persistentFlags, flags...)
+	}
+}
+
+func WithSuggestionDistance(distance int) CommandOption {
+	return func(c *Command) {
+		c.suggestionDistance = distance
+	}
+}
+
+func WithoutSuggestions() CommandOption {
+	return func(c *Command) {
+		c.disableSuggestions = true
+	}
+}
+
+func WithVersion(version string) CommandOption {
+	return func(c *Command) {
+		c.version = version
+	}
+}
+
+func WithDeprecated(message string) CommandOption {
+	return func(c *Command) {
+		c.deprecated = message
+	}
+}
+
+func WithDeprecatedAlias(alias, message string) CommandOption {
+	return func(c *Command) {
+		if c.deprecatedAliases == nil {
+			c.deprecatedAliases = make(map[string]string)
+		}
+		c.deprecatedAliases[alias] = message
+		if !contains(c.aliases, alias) {
+			c.aliases = append(c.aliases, alias)
+		}
+	}
+}
+
+func WithRemovedIn(version string) CommandOption {
+	return func(c *Command) {
+		c.removedIn = version
+	}
+}
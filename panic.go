@@ -0,0 +1,142 @@
+package gocli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+const redactedValue = "[REDACTED]"
+
+var secretFlagWords = []string{"password", "passwd", "token", "secret", "key"}
+
+func (c *Command) panicRecoveryEnabled() bool {
+	if c.panicRecovery {
+		return true
+	}
+
+	if c.parent != nil {
+		return c.parent.panicRecoveryEnabled()
+	}
+
+	return false
+}
+
+func (c *Command) crashReportDirectory() string {
+	if c.crashReportDir != "" {
+		return c.crashReportDir
+	}
+
+	if c.parent != nil {
+		return c.parent.crashReportDirectory()
+	}
+
+	return ""
+}
+
+// recovered turns a recovered panic value into a PanicError, writes the crash
+// report when a directory is configured and tells the user where to find it.
+func (c *Command) recovered(value interface{}, args []string) *PanicError {
+	panicErr := &PanicError{
+		Value:       value,
+		Stack:       debug.Stack(),
+		CommandPath: c.CommandPath(),
+		Args:        c.redactArgs(args),
+	}
+
+	w := c.ErrOrStderr()
+	fmt.Fprintf(w, "%s crashed unexpectedly: %v\n", panicErr.CommandPath, value)
+
+	if dir := c.crashReportDirectory(); dir != "" {
+		path, err := c.writeCrashReport(dir, panicErr)
+		if err != nil {
+			fmt.Fprintf(w, "Could not write crash report: %v\n", err)
+		} else {
+			panicErr.ReportPath = path
+			fmt.Fprintf(w, "A crash report was written to %s, please include it when reporting this issue.\n", path)
+		}
+	}
+
+	return panicErr
+}
+
+func (c *Command) writeCrashReport(dir string, panicErr *PanicError) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	pattern := fmt.Sprintf("%s-crash-%s-*.log", c.Root().commandName, time.Now().Format("20060102-150405"))
+	f, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Command: %s\n", panicErr.CommandPath)
+	fmt.Fprintf(&b, "Args: %s\n", strings.Join(panicErr.Args, " "))
+	if version := c.Version(); version != "" {
+		fmt.Fprintf(&b, "Version: %s\n", version)
+	}
+	fmt.Fprintf(&b, "Go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "Time: %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&b, "\npanic: %v\n\n%s", panicErr.Value, panicErr.Stack)
+
+	if _, err := f.WriteString(b.String()); err != nil {
+		return "", err
+	}
+
+	return filepath.Abs(f.Name())
+}
+
+func (c *Command) redactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	copy(redacted, args)
+
+	for i := 0; i < len(redacted); i++ {
+		arg := redacted[i]
+		if arg == "--" {
+			break
+		}
+
+		if !isFlagArg(arg) {
+			continue
+		}
+
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "--") {
+			if f, ok := c.lookupShorthand(name); ok {
+				name = f.Name
+			}
+		}
+
+		if !isSecretFlag(name) {
+			continue
+		}
+
+		if hasValue {
+			redacted[i] = arg[:strings.Index(arg, "=")+1] + redactedValue
+		} else if i+1 < len(redacted) && !isFlagArg(redacted[i+1]) {
+			if f, ok := c.lookupFlag(name); !ok || f.Type != Bool {
+				i++
+				redacted[i] = redactedValue
+			}
+		}
+	}
+
+	return redacted
+}
+
+func isSecretFlag(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range secretFlagWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
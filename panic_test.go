@@ -0,0 +1,168 @@
+package gocli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommand_PanicRecovery(t *testing.T) {
+	var errOut bytes.Buffer
+	rootCmd := NewCommand(WithName("toolbox"), WithPanicRecovery())
+	rootCmd.AddCommand(NewCommand(
+		WithName("login"),
+		WithFlag(
+			StringFlag("user", "", "User name"),
+			StringFlag("password", "", "Password").WithShorthand("p"),
+		),
+		WithRun(func(cmd *Command, args []string) error {
+			var m map[string]int
+			m["boom"]++
+			return nil
+		}),
+	))
+	rootCmd.SetErr(&errOut)
+
+	rootCmd.SetArgs([]string{"login", "--user", "alice", "--password", "hunter2"})
+
+	err := rootCmd.Execute()
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected PanicError, got %v", err)
+	}
+
+	if panicErr.CommandPath != "toolbox login" {
+		t.Errorf("expected command path 'toolbox login', got '%s'", panicErr.CommandPath)
+	}
+
+	if got := strings.Join(panicErr.Args, " "); got != "login --user alice --password [REDACTED]" {
+		t.Errorf("expected redacted args, got '%s'", got)
+	}
+
+	if !bytes.Contains(panicErr.Stack, []byte("panic")) {
+		t.Error("expected stack trace to be captured")
+	}
+
+	if !strings.Contains(errOut.String(), "toolbox login crashed unexpectedly") {
+		t.Errorf("expected user-facing message, got %q", errOut.String())
+	}
+
+	if panicErr.ReportPath != "" {
+		t.Errorf("expected no crash report without a directory, got '%s'", panicErr.ReportPath)
+	}
+}
+
+func TestCommand_PanicCrashReport(t *testing.T) {
+	var errOut bytes.Buffer
+	dir := filepath.Join(t.TempDir(), "crashes")
+	rootCmd := NewCommand(
+		WithName("toolbox"),
+		WithPanicRecovery(),
+		WithCrashReportDir(dir),
+		WithVersion("1.2.3"),
+	)
+	rootCmd.AddCommand(NewCommand(
+		WithName("login"),
+		WithFlag(
+			StringFlag("user", "", "User name"),
+			StringFlag("password", "", "Password").WithShorthand("p"),
+		),
+		WithRun(func(cmd *Command, args []string) error {
+			var m map[string]int
+			m["boom"]++
+			return nil
+		}),
+	))
+	rootCmd.SetErr(&errOut)
+
+	rootCmd.SetArgs([]string{"login", "-p", "hunter2"})
+
+	var panicErr *PanicError
+	if err := rootCmd.Execute(); !errors.As(err, &panicErr) {
+		t.Fatalf("expected PanicError, got %v", err)
+	}
+
+	if filepath.Dir(panicErr.ReportPath) != dir {
+		t.Fatalf("expected crash report in %s, got '%s'", dir, panicErr.ReportPath)
+	}
+
+	report, err := os.ReadFile(panicErr.ReportPath)
+	if err != nil {
+		t.Fatalf("failed to read crash report: %v", err)
+	}
+
+	for _, want := range []string{"Command: toolbox login", "Version: 1.2.3", "assignment to entry in nil map", "-p [REDACTED]"} {
+		if !strings.Contains(string(report), want) {
+			t.Errorf("expected crash report to contain %q, got:\n%s", want, report)
+		}
+	}
+
+	if strings.Contains(string(report), "hunter2") {
+		t.Error("crash report should not contain secrets")
+	}
+
+	if !strings.Contains(errOut.String(), panicErr.ReportPath) {
+		t.Errorf("expected message to point at the report, got %q", errOut.String())
+	}
+}
+
+func TestCommand_PanicRecoveryRunsFinalizers(t *testing.T) {
+	finallyExecuted := false
+
+	cmd := NewCommand(
+		WithName("test"),
+		WithPanicRecovery(),
+		WithRun(func(cmd *Command, args []string) error {
+			panic("boom")
+		}),
+		WithFinally(func(cmd *Command, args []string) error {
+			finallyExecuted = true
+			return nil
+		}),
+	)
+
+	cmd.SetArgs([]string{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	var panicErr *PanicError
+	if err := cmd.Execute(); !errors.As(err, &panicErr) || panicErr.Value != "boom" {
+		t.Fatalf("expected PanicError with value 'boom', got %v", err)
+	}
+
+	if !finallyExecuted {
+		t.Error("finally should run before the panic is recovered")
+	}
+}
+
+func TestCommand_RedactArgs(t *testing.T) {
+	cmd := NewCommand(
+		WithName("test"),
+		WithFlag(
+			StringFlag("api-key", "", "API key").WithShorthand("k"),
+			BoolFlag("show-token", false, "Show token"),
+		),
+	)
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--api-key=abc"}, "--api-key=[REDACTED]"},
+		{[]string{"--api-key", "abc", "pos"}, "--api-key [REDACTED] pos"},
+		{[]string{"-k", "abc"}, "-k [REDACTED]"},
+		{[]string{"--show-token", "pos"}, "--show-token pos"},
+		{[]string{"--GITHUB_TOKEN", "abc"}, "--GITHUB_TOKEN [REDACTED]"},
+		{[]string{"--", "--password", "literal"}, "-- --password literal"},
+		{[]string{"--name", "bob"}, "--name bob"},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(cmd.redactArgs(tt.args), " "); got != tt.want {
+			t.Errorf("redactArgs(%v) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package gocli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Arg struct {
+	Name     string
+	Type     ValueType
+	Required bool
+	Variadic bool
+}
+
+func (a Arg) usage() string {
+	name := a.Name
+	if a.Variadic {
+		name += "..."
+	}
+	if a.Required {
+		return "<" + name + ">"
+	}
+	return "[" + name + "]"
+}
+
+func (c *Command) Args() []Arg {
+	return c.positionals
+}
+
+func (c *Command) argsUsage() string {
+	parts := make([]string, 0, len(c.positionals))
+	for _, arg := range c.positionals {
+		parts = append(parts, arg.usage())
+	}
+	return strings.Join(parts, " ")
+}
+
+// parseArgs checks args against the declared positionals and stores the
+// converted values. Variadic positionals collect a slice of their type.
+func (c *Command) parseArgs(args []string) error {
+	if len(c.positionals) == 0 {
+		return nil
+	}
+
+	values := make(map[string]interface{})
+
+	i := 0
+	for _, arg := range c.positionals {
+		if i >= len(args) {
+			if arg.Required {
+				return &ArgError{Position: i + 1, Name: arg.Name, Reason: "required argument is missing"}
+			}
+			continue
+		}
+
+		if !arg.Variadic {
+			value, err := arg.Type.parse(args[i])
+			if err != nil {
+				return &ArgError{Position: i + 1, Name: arg.Name, Value: args[i], Reason: fmt.Sprintf("invalid %s value %q", arg.Type, args[i])}
+			}
+			values[arg.Name] = value
+			i++
+			continue
+		}
+
+		variadic, err := arg.parseVariadic(args[i:], i)
+		if err != nil {
+			return err
+		}
+		values[arg.Name] = variadic
+		i = len(args)
+	}
+
+	if i < len(args) {
+		return &ArgError{Position: i + 1, Value: args[i], Reason: "unexpected argument"}
+	}
+
+	c.argValues = values
+	return nil
+}
+
+func (a Arg) parseVariadic(args []string, offset int) (interface{}, error) {
+	var (
+		strs      []string
+		ints      []int
+		bools     []bool
+		durations []time.Duration
+	)
+
+	for j, raw := range args {
+		value, err := a.Type.parse(raw)
+		if err != nil {
+			return nil, &ArgError{Position: offset + j + 1, Name: a.Name, Value: raw, Reason: fmt.Sprintf("invalid %s value %q", a.Type, raw)}
+		}
+
+		switch v := value.(type) {
+		case string:
+			strs = append(strs, v)
+		case []string:
+			strs = append(strs, v...)
+		case int:
+			ints = append(ints, v)
+		case bool:
+			bools = append(bools, v)
+		case time.Duration:
+			durations = append(durations, v)
+		}
+	}
+
+	switch a.Type {
+	case Int:
+		return ints, nil
+	case Bool:
+		return bools, nil
+	case Duration:
+		return durations, nil
+	default:
+		return strs, nil
+	}
+}
+
+func (c *Command) ArgValue(name string) interface{} {
+	return c.argValues[name]
+}
+
+func (c *Command) ArgString(name string) string {
+	value, _ := c.argValues[name].(string)
+	return value
+}
+
+func (c *Command) ArgInt(name string) int {
+	value, _ := c.argValues[name].(int)
+	return value
+}
+
+func (c *Command) ArgBool(name string) bool {
+	value, _ := c.argValues[name].(bool)
+	return value
+}
+
+func (c *Command) ArgDuration(name string) time.Duration {
+	value, _ := c.argValues[name].(time.Duration)
+	return value
+}
+
+func (c *Command) ArgStrings(name string) []string {
+	value, _ := c.argValues[name].([]string)
+	return value
+}
+
+func (c *Command) ArgInts(name string) []int {
+	value, _ := c.argValues[name].([]int)
+	return value
+}
+
+func (c *Command) validatePositionals(report func(format string, args ...interface{})) {
+	seen := make(map[string]bool)
+	optional := false
+
+	for i, arg := range c.positionals {
+		if seen[arg.Name] {
+			report("%q: argument %q is declared more than once", c.CommandPath(), arg.Name)
+		}
+		seen[arg.Name] = true
+
+		if arg.Variadic && i != len(c.positionals)-1 {
+			report("%q: variadic argument %q must be the last argument", c.CommandPath(), arg.Name)
+		}
+
+		if arg.Required && optional {
+			report("%q: required argument %q follows an optional argument", c.CommandPath(), arg.Name)
+		}
+		optional = optional || !arg.Required
+	}
+}
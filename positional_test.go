@@ -0,0 +1,196 @@
+package gocli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommand_ArgsSchema(t *testing.T) {
+	var port int
+	var files []string
+
+	rootCmd := NewCommand(WithName("app"))
+	rootCmd.AddCommand(NewCommand(
+		WithName("serve"),
+		WithArgs(
+			Arg{Name: "port", Type: Int, Required: true},
+			Arg{Name: "files", Variadic: true},
+		),
+		WithRun(func(cmd *Command, args []string) error {
+			port = cmd.ArgInt("port")
+			files = cmd.ArgStrings("files")
+			return nil
+		}),
+	))
+
+	rootCmd.SetArgs([]string{"serve", "8080", "a.txt", "b.txt"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if port != 8080 {
+		t.Errorf("expected port 8080, got %d", port)
+	}
+
+	if strings.Join(files, ",") != "a.txt,b.txt" {
+		t.Errorf("expected files [a.txt b.txt], got %v", files)
+	}
+}
+
+func TestCommand_ArgsSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		position int
+		argName  string
+		message  string
+	}{
+		{"missing required", []string{"serve"}, 1, "port", "argument <port> at position 1: required argument is missing"},
+		{"invalid type", []string{"serve", "http"}, 1, "port", `argument <port> at position 1: invalid int value "http"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runExecuted := false
+			rootCmd := NewCommand(WithName("app"))
+			rootCmd.AddCommand(NewCommand(
+				WithName("serve"),
+				WithArgs(
+					Arg{Name: "port", Type: Int, Required: true},
+					Arg{Name: "files", Variadic: true},
+				),
+				WithRun(func(cmd *Command, args []string) error {
+					runExecuted = true
+					return nil
+				}),
+			))
+
+			rootCmd.SetArgs(tt.args)
+
+			err := rootCmd.Execute()
+
+			var argErr *ArgError
+			if !errors.As(err, &argErr) {
+				t.Fatalf("expected ArgError, got %v", err)
+			}
+
+			if argErr.Position != tt.position || argErr.Name != tt.argName {
+				t.Errorf("expected position %d for %q, got %d for %q", tt.position, tt.argName, argErr.Position, argErr.Name)
+			}
+
+			if err.Error() != tt.message {
+				t.Errorf("expected message %q, got %q", tt.message, err.Error())
+			}
+
+			if ExitCode(err) != ExitUsage {
+				t.Errorf("expected usage exit code, got %d", ExitCode(err))
+			}
+
+			if runExecuted {
+				t.Error("run should not execute with invalid args")
+			}
+		})
+	}
+}
+
+func TestCommand_ArgsSchemaTypedVariadicAndOptional(t *testing.T) {
+	var (
+		name    string
+		retries int
+		timeout time.Duration
+		counts  []int
+	)
+
+	cmd := NewCommand(
+		WithName("job"),
+		WithArgs(
+			Arg{Name: "name", Required: true},
+			Arg{Name: "timeout", Type: Duration, Required: true},
+			Arg{Name: "retries", Type: Int},
+			Arg{Name: "counts", Type: Int, Variadic: true},
+		),
+		WithRun(func(cmd *Command, args []string) error {
+			name = cmd.ArgString("name")
+			timeout = cmd.ArgDuration("timeout")
+			retries = cmd.ArgInt("retries")
+			counts = cmd.ArgInts("counts")
+			return nil
+		}),
+	)
+
+	cmd.SetArgs([]string{"nightly", "5m"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if name != "nightly" || timeout != 5*time.Minute || retries != 0 || counts != nil {
+		t.Errorf("unexpected values: name=%q timeout=%s retries=%d counts=%v", name, timeout, retries, counts)
+	}
+
+	cmd.SetArgs([]string{"nightly", "5m", "3", "1", "x"})
+
+	var argErr *ArgError
+	if err := cmd.Execute(); !errors.As(err, &argErr) || argErr.Position != 5 || argErr.Name != "counts" {
+		t.Errorf("expected ArgError for counts at position 5, got %v", err)
+	}
+}
+
+func TestCommand_ArgsSchemaUnexpectedArgument(t *testing.T) {
+	cmd := NewCommand(
+		WithName("get"),
+		WithArgs(Arg{Name: "key", Required: true}),
+		WithRun(func(cmd *Command, args []string) error { return nil }),
+	)
+
+	cmd.SetArgs([]string{"a", "b"})
+
+	err := cmd.Execute()
+	if err == nil || err.Error() != `unexpected argument "b" at position 2` {
+		t.Errorf("expected unexpected argument error, got %v", err)
+	}
+}
+
+func TestCommand_ArgsSchemaUsage(t *testing.T) {
+	rootCmd := NewCommand(WithName("app"))
+	serveCmd := NewCommand(
+		WithName("serve"),
+		WithArgs(
+			Arg{Name: "port", Type: Int, Required: true},
+			Arg{Name: "files", Variadic: true},
+		),
+	)
+	rootCmd.AddCommand(serveCmd)
+
+	if got := serveCmd.UseLine(); got != "app serve <port> [files...]" {
+		t.Errorf("expected usage 'app serve <port> [files...]', got '%s'", got)
+	}
+}
+
+func TestCommand_ArgsSchemaValidate(t *testing.T) {
+	cmd := NewCommand(
+		WithName("app"),
+		WithArgs(
+			Arg{Name: "files", Variadic: true},
+			Arg{Name: "target", Required: true},
+			Arg{Name: "target"},
+		),
+	)
+
+	err := cmd.Validate()
+	if err == nil {
+		t.Fatal("expected validation error, got nil")
+	}
+
+	for _, want := range []string{
+		`variadic argument "files" must be the last argument`,
+		`required argument "target" follows an optional argument`,
+		`argument "target" is declared more than once`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected problem %q, got:\n%s", want, err)
+		}
+	}
+}
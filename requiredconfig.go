@@ -0,0 +1,79 @@
+package gocli
+
+// RequiredConfig returns the keys required by the command and its ancestors.
+func (c *Command) RequiredConfig() []string {
+	var keys []string
+	if c.parent != nil {
+		keys = c.parent.RequiredConfig()
+	}
+	return append(keys, c.requiredConfig...)
+}
+
+// checkRequiredConfig reads every required key through Config(), including
+// declared keys marked Required, and reports all missing ones at once. An
+// empty string counts as missing, so a flag's empty default doesn't satisfy it.
+func (c *Command) checkRequiredConfig() error {
+	declared := make(map[string]ConfigKey)
+	required := make([]ConfigKey, 0)
+	seen := make(map[string]bool)
+
+	for _, key := range c.ConfigKeys() {
+		declared[key.Key] = key
+		if key.Required && !seen[key.Key] {
+			seen[key.Key] = true
+			required = append(required, key)
+		}
+	}
+
+	for _, name := range c.RequiredConfig() {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		key, ok := declared[name]
+		if !ok {
+			key = ConfigKey{Key: name}
+		}
+		required = append(required, key)
+	}
+
+	if len(required) == 0 {
+		return nil
+	}
+
+	cfg := c.Config()
+	missing := make([]MissingConfigKey, 0)
+
+	for _, key := range required {
+		if value, found := readConfig(cfg, key.Key); found && value != "" {
+			continue
+		}
+		missing = append(missing, c.missingConfigKey(key))
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return &MissingConfigError{Keys: missing}
+}
+
+func (c *Command) missingConfigKey(key ConfigKey) MissingConfigKey {
+	missing := MissingConfigKey{Key: key.Key}
+
+	for _, source := range c.configSources(key) {
+		switch source.kind {
+		case sourceFlag:
+			missing.Flag = "--" + source.location
+		case sourceEnv:
+			missing.Env = append(missing.Env, source.location)
+		case sourceFile:
+			if source.location != "" {
+				missing.Files = append(missing.Files, source.location)
+			}
+		}
+	}
+
+	return missing
+}
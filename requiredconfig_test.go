@@ -0,0 +1,103 @@
+package gocli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnemade360/go-config/providers/env"
+	"github.com/gnemade360/go-config/providers/file"
+	"github.com/gnemade360/go-config/providers/sequential"
+)
+
+func TestCommand_RequiredConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("database:\n  user: admin\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	newTree := func(run CommandFunc) *Command {
+		rootCmd := NewCommand(
+			WithName("app"),
+			WithConfigProvider(sequential.New(sequential.WithProviders(
+				&env.Provider{Prefix: "APP_", Transform: env.ToUpper},
+				file.New(file.WithFilePath(path)),
+			))),
+			WithConfigKey(ConfigKey{Key: "api.token", Env: "API_TOKEN", Required: true}),
+		)
+		rootCmd.AddCommand(NewCommand(
+			WithName("connect"),
+			WithFlag(StringFlag("database.host", "", "Database host")),
+			WithRequiredConfig("database.host", "database.port", "database.user"),
+			WithPreRun(run),
+			WithRun(run),
+		))
+		return rootCmd
+	}
+
+	t.Run("missing keys", func(t *testing.T) {
+		rootCmd := newTree(func(cmd *Command, args []string) error {
+			t.Error("lifecycle should not start with missing config")
+			return nil
+		})
+
+		rootCmd.SetArgs([]string{"connect"})
+		err := rootCmd.Execute()
+
+		var missingErr *MissingConfigError
+		if !errors.As(err, &missingErr) {
+			t.Fatalf("expected MissingConfigError, got %v", err)
+		}
+
+		want := "missing required config:\n" +
+			"  - api.token (env API_TOKEN, APP_API_TOKEN; file " + path + ")\n" +
+			"  - database.host (flag --database.host; env APP_DATABASE_HOST; file " + path + ")\n" +
+			"  - database.port (env APP_DATABASE_PORT; file " + path + ")"
+		if err.Error() != want {
+			t.Errorf("expected:\n%s\ngot:\n%s", want, err.Error())
+		}
+
+		if ExitCode(err) != ExitConfig {
+			t.Errorf("expected exit code %d, got %d", ExitConfig, ExitCode(err))
+		}
+	})
+
+	t.Run("all keys set", func(t *testing.T) {
+		t.Setenv("API_TOKEN", "secret")
+		t.Setenv("APP_DATABASE_PORT", "5432")
+
+		ran := false
+		rootCmd := newTree(func(cmd *Command, args []string) error {
+			ran = true
+			return nil
+		})
+
+		rootCmd.SetArgs([]string{"connect", "--database.host", "db.local"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+
+		if !ran {
+			t.Error("expected the lifecycle to run")
+		}
+	})
+}
+
+func TestCommand_RequiredConfigInherited(t *testing.T) {
+	rootCmd := NewCommand(WithName("app"), WithRequiredConfig("region"))
+	subCmd := NewCommand(WithName("deploy"), WithRequiredConfig("service", "region"))
+	rootCmd.AddCommand(subCmd)
+
+	if got := subCmd.RequiredConfig(); len(got) != 3 || got[0] != "region" || got[1] != "service" {
+		t.Errorf("expected [region service region], got %v", got)
+	}
+
+	subCmd.run = func(cmd *Command, args []string) error { return nil }
+	rootCmd.SetArgs([]string{"deploy"})
+
+	err := rootCmd.Execute()
+	if err == nil || err.Error() != "missing required config:\n  - region\n  - service" {
+		t.Errorf("expected each missing key once, got %v", err)
+	}
+}
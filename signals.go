@@ -0,0 +1,100 @@
+package gocli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	signalNotify = signal.Notify
+	signalStop   = signal.Stop
+)
+
+func (c *Command) signalGracePeriod() (time.Duration, bool) {
+	if c.signalHandling {
+		return c.signalGrace, true
+	}
+
+	if c.parent != nil {
+		return c.parent.signalGracePeriod()
+	}
+
+	return 0, false
+}
+
+// handleSignals derives a context that is cancelled on the first SIGINT or
+// SIGTERM. A second signal, or the grace period running out, exits the process.
+// The returned function stops listening and reports an interrupted execution as
+// an InterruptedError.
+func (c *Command) handleSignals(ctx context.Context, grace time.Duration) (context.Context, func(error) error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	signals := make(chan os.Signal, 2)
+	signalNotify(signals, os.Interrupt, syscall.SIGTERM)
+
+	var (
+		mu       sync.Mutex
+		received os.Signal
+	)
+	done := make(chan struct{})
+
+	go func() {
+		var deadline <-chan time.Time
+
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-signals:
+				mu.Lock()
+				first := received == nil
+				if first {
+					received = sig
+				}
+				mu.Unlock()
+
+				if !first {
+					c.forceExit(sig, "received second signal")
+					return
+				}
+
+				fmt.Fprintf(c.ErrOrStderr(), "\nReceived %v, shutting down (press Ctrl+C again to force)\n", sig)
+				cancel()
+				if grace > 0 {
+					deadline = time.After(grace)
+				}
+			case <-deadline:
+				mu.Lock()
+				sig := received
+				mu.Unlock()
+				c.forceExit(sig, fmt.Sprintf("shutdown did not finish within %s", grace))
+				return
+			}
+		}
+	}()
+
+	finish := func(err error) error {
+		signalStop(signals)
+		close(done)
+		cancel()
+
+		mu.Lock()
+		defer mu.Unlock()
+		if received == nil {
+			return err
+		}
+		return &InterruptedError{Signal: received, Err: err}
+	}
+
+	return ctx, finish
+}
+
+func (c *Command) forceExit(sig os.Signal, reason string) {
+	fmt.Fprintf(c.ErrOrStderr(), "Forced exit: %s\n", reason)
+	osExit(ExitCode(&InterruptedError{Signal: sig}))
+}
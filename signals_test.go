@@ -0,0 +1,198 @@
+package gocli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fakeSignals replaces signal registration so tests can deliver signals
+// without signalling the test process.
+func fakeSignals(t *testing.T) chan<- os.Signal {
+	t.Helper()
+
+	registered := make(chan chan<- os.Signal, 1)
+	oldNotify, oldStop := signalNotify, signalStop
+	signalNotify = func(c chan<- os.Signal, sig ...os.Signal) { registered <- c }
+	signalStop = func(c chan<- os.Signal) {}
+	t.Cleanup(func() { signalNotify, signalStop = oldNotify, oldStop })
+
+	deliver := make(chan os.Signal)
+	go func() {
+		c := <-registered
+		for sig := range deliver {
+			c <- sig
+		}
+	}()
+	return deliver
+}
+
+func TestCommand_SignalCancelsContext(t *testing.T) {
+	signals := fakeSignals(t)
+	var order []string
+	var errOut bytes.Buffer
+
+	cmd := NewCommand(
+		WithName("watch"),
+		WithSignalHandling(time.Minute),
+		WithRun(func(cmd *Command, args []string) error {
+			signals <- os.Interrupt
+			<-cmd.Context().Done()
+			order = append(order, "run")
+			return nil
+		}),
+		WithPostRun(func(cmd *Command, args []string) error {
+			order = append(order, "postRun")
+			return nil
+		}),
+		WithFinally(func(cmd *Command, args []string) error {
+			order = append(order, "finally")
+			return nil
+		}),
+	)
+	cmd.SetArgs([]string{})
+	cmd.SetErr(&errOut)
+
+	err := cmd.Execute()
+
+	var interrupted *InterruptedError
+	if !errors.As(err, &interrupted) {
+		t.Fatalf("expected InterruptedError, got %v", err)
+	}
+
+	if ExitCode(err) != ExitInterrupted {
+		t.Errorf("expected exit code %d, got %d", ExitInterrupted, ExitCode(err))
+	}
+
+	if strings.Join(order, ",") != "run,postRun,finally" {
+		t.Errorf("expected lifecycle to finish after the signal, got %v", order)
+	}
+
+	if !strings.Contains(errOut.String(), "shutting down") {
+		t.Errorf("expected shutdown message, got %q", errOut.String())
+	}
+}
+
+func TestCommand_SignalHandlingOnSubcommand(t *testing.T) {
+	signals := fakeSignals(t)
+
+	rootCmd := NewCommand(WithName("app"))
+	rootCmd.AddCommand(NewCommand(
+		WithName("serve"),
+		WithSignalHandling(time.Minute),
+		WithRun(func(cmd *Command, args []string) error {
+			select {
+			case signals <- os.Interrupt:
+			case <-time.After(time.Second):
+				return errors.New("signal handling was not installed")
+			}
+			<-cmd.Context().Done()
+			return nil
+		}),
+	))
+	rootCmd.SetArgs([]string{"serve"})
+	rootCmd.SetErr(&bytes.Buffer{})
+
+	var interrupted *InterruptedError
+	if err := rootCmd.Execute(); !errors.As(err, &interrupted) {
+		t.Fatalf("expected InterruptedError, got %v", err)
+	}
+}
+
+func TestCommand_SignalWrapsCommandError(t *testing.T) {
+	signals := fakeSignals(t)
+
+	cmd := NewCommand(
+		WithName("watch"),
+		WithSignalHandling(time.Minute),
+		WithRun(func(cmd *Command, args []string) error {
+			signals <- syscall.SIGTERM
+			<-cmd.Context().Done()
+			return cmd.Context().Err()
+		}),
+	)
+	cmd.SetArgs([]string{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	err := cmd.Execute()
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the command's error to be wrapped, got %v", err)
+	}
+
+	if ExitCode(err) != 128+int(syscall.SIGTERM) {
+		t.Errorf("expected exit code %d, got %d", 128+int(syscall.SIGTERM), ExitCode(err))
+	}
+}
+
+func TestCommand_SignalForceExit(t *testing.T) {
+	tests := []struct {
+		name   string
+		grace  time.Duration
+		second bool
+		reason string
+	}{
+		{"second signal", time.Minute, true, "received second signal"},
+		{"grace period", 10 * time.Millisecond, false, "shutdown did not finish within 10ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signals := fakeSignals(t)
+
+			exited := make(chan int, 1)
+			oldExit := osExit
+			osExit = func(code int) { exited <- code }
+			defer func() { osExit = oldExit }()
+
+			var errOut bytes.Buffer
+			var code int
+			cmd := NewCommand(
+				WithName("serve"),
+				WithSignalHandling(tt.grace),
+				WithRun(func(cmd *Command, args []string) error {
+					signals <- os.Interrupt
+					<-cmd.Context().Done()
+					if tt.second {
+						signals <- os.Interrupt
+					}
+					code = <-exited
+					return nil
+				}),
+			)
+			cmd.SetArgs([]string{})
+			cmd.SetErr(&errOut)
+
+			cmd.Execute()
+
+			if code != ExitInterrupted {
+				t.Errorf("expected forced exit with %d, got %d", ExitInterrupted, code)
+			}
+
+			if !strings.Contains(errOut.String(), tt.reason) {
+				t.Errorf("expected %q in output, got %q", tt.reason, errOut.String())
+			}
+		})
+	}
+}
+
+func TestCommand_NoSignalNoInterruptedError(t *testing.T) {
+	fakeSignals(t)
+
+	cmd := NewCommand(
+		WithName("test"),
+		WithSignalHandling(time.Second),
+		WithRun(func(cmd *Command, args []string) error {
+			return nil
+		}),
+	)
+	cmd.SetArgs([]string{})
+
+	if err := cmd.Execute(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package gocli
+
+import (
+	"io"
+	"os"
+)
+
+func (c *Command) SetArgs(args []string) {
+	c.args = args
+}
+
+func (c *Command) SetIn(in io.Reader) {
+	c.in = in
+}
+
+func (c *Command) SetOut(out io.Writer) {
+	c.out = out
+}
+
+func (c *Command) SetErr(errOut io.Writer) {
+	c.errOut = errOut
+}
+
+func (c *Command) InOrStdin() io.Reader {
+	if c.inv != nil && c.inv.In != nil {
+		return c.inv.In
+	}
+
+	if c.in != nil {
+		return c.in
+	}
+
+	if c.parent != nil {
+		return c.parent.InOrStdin()
+	}
+
+	return os.Stdin
+}
+
+func (c *Command) OutOrStdout() io.Writer {
+	if c.inv != nil && c.inv.Out != nil {
+		return c.inv.Out
+	}
+
+	if c.out != nil {
+		return c.out
+	}
+
+	if c.parent != nil {
+		return c.parent.OutOrStdout()
+	}
+
+	return os.Stdout
+}
+
+func (c *Command) ErrOrStderr() io.Writer {
+	if c.inv != nil && c.inv.ErrOut != nil {
+		return c.inv.ErrOut
+	}
+
+	if c.errOut != nil {
+		return c.errOut
+	}
+
+	if c.parent != nil {
+		return c.parent.ErrOrStderr()
+	}
+
+	return os.Stderr
+}
@@ -0,0 +1,174 @@
+package gocli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCommand_StreamsInherited(t *testing.T) {
+	var out, errOut bytes.Buffer
+	in := strings.NewReader("hello from stdin")
+
+	rootCmd := NewCommand(WithName("toolbox"))
+	rootCmd.SetIn(in)
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&errOut)
+
+	echoCmd := NewCommand(
+		WithName("echo"),
+		WithRun(func(cmd *Command, args []string) error {
+			data, err := io.ReadAll(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), string(data))
+			fmt.Fprint(cmd.ErrOrStderr(), "done")
+			return nil
+		}),
+	)
+	rootCmd.AddCommand(echoCmd)
+
+	rootCmd.SetArgs([]string{"echo"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if out.String() != "hello from stdin" {
+		t.Errorf("expected stdin echoed to out, got %q", out.String())
+	}
+
+	if errOut.String() != "done" {
+		t.Errorf("expected 'done' on err, got %q", errOut.String())
+	}
+}
+
+func TestCommand_StreamsOverriddenBySubcommand(t *testing.T) {
+	var rootOut, subOut bytes.Buffer
+
+	rootCmd := NewCommand(WithName("toolbox"))
+	rootCmd.SetOut(&rootOut)
+
+	subCmd := NewCommand(WithName("sub"))
+	subCmd.SetOut(&subOut)
+	rootCmd.AddCommand(subCmd)
+
+	if subCmd.OutOrStdout() != &subOut {
+		t.Error("subcommand should prefer its own output stream")
+	}
+
+	if rootCmd.OutOrStdout() != &rootOut {
+		t.Error("root should keep its own output stream")
+	}
+}
+
+func TestCommand_StreamsDefaultToProcess(t *testing.T) {
+	cmd := NewCommand(WithName("toolbox"))
+
+	if cmd.InOrStdin() != os.Stdin {
+		t.Error("expected os.Stdin by default")
+	}
+
+	if cmd.OutOrStdout() != os.Stdout {
+		t.Error("expected os.Stdout by default")
+	}
+
+	if cmd.ErrOrStderr() != os.Stderr {
+		t.Error("expected os.Stderr by default")
+	}
+}
+
+func TestCommand_HelpAndCompletionWriteToOut(t *testing.T) {
+	newTree := func(out *bytes.Buffer) *Command {
+		rootCmd := NewCommand(WithName("toolbox"), WithShort("A CLI toolbox"))
+		rootCmd.AddCommand(NewCommand(WithName("version"), WithShort("Print version information")))
+		rootCmd.SetOut(out)
+		return rootCmd
+	}
+
+	t.Run("help", func(t *testing.T) {
+		var out bytes.Buffer
+		rootCmd := newTree(&out)
+		rootCmd.SetArgs([]string{"--help"})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+
+		if !strings.Contains(out.String(), "Available Commands:") {
+			t.Errorf("expected help on out, got:\n%s", out.String())
+		}
+	})
+
+	t.Run("complete", func(t *testing.T) {
+		var out bytes.Buffer
+		rootCmd := newTree(&out)
+		rootCmd.SetArgs([]string{completeCommandName, "ver"})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+
+		if !strings.HasPrefix(out.String(), "version\t") {
+			t.Errorf("expected completion on out, got:\n%s", out.String())
+		}
+	})
+}
+
+func TestCommand_SetArgsParallel(t *testing.T) {
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			rootCmd := NewCommand(WithName("toolbox"))
+			rootCmd.AddCommand(NewCommand(
+				WithName("echo"),
+				WithRun(func(cmd *Command, args []string) error {
+					fmt.Fprint(cmd.OutOrStdout(), strings.Join(args, " "))
+					return nil
+				}),
+			))
+			rootCmd.SetOut(&out)
+			rootCmd.SetArgs([]string{"echo", name})
+
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			if out.String() != name {
+				t.Errorf("expected %q, got %q", name, out.String())
+			}
+		})
+	}
+}
+
+func TestCommand_SetArgsNilFallsBackToProcessArgs(t *testing.T) {
+	originalArgs := os.Args
+	defer func() { os.Args = originalArgs }()
+	os.Args = []string{"toolbox", "from-process"}
+
+	var received []string
+	cmd := NewCommand(
+		WithName("toolbox"),
+		WithRun(func(cmd *Command, args []string) error {
+			received = args
+			return nil
+		}),
+	)
+
+	cmd.SetArgs([]string{"from-set-args"})
+	cmd.SetArgs(nil)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if strings.Join(received, ",") != "from-process" {
+		t.Errorf("expected args from os.Args, got %v", received)
+	}
+}
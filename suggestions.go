@@ -0,0 +1,76 @@
+package gocli
+
+import "strings"
+
+const defaultSuggestionDistance = 2
+
+func (c *Command) SuggestionsFor(typed string) []string {
+	if c.disableSuggestions {
+		return nil
+	}
+
+	maxDistance := c.suggestionMaxDistance()
+	suggestions := make([]string, 0)
+
+	for _, cmd := range c.visibleCommands() {
+		for _, name := range append([]string{cmd.commandName}, cmd.visibleAliases()...) {
+			if isSuggestion(typed, name, maxDistance) {
+				suggestions = append(suggestions, cmd.commandName)
+				break
+			}
+		}
+	}
+
+	return suggestions
+}
+
+func (c *Command) suggestionMaxDistance() int {
+	if c.suggestionDistance > 0 {
+		return c.suggestionDistance
+	}
+
+	if c.parent != nil {
+		return c.parent.suggestionMaxDistance()
+	}
+
+	return defaultSuggestionDistance
+}
+
+func isSuggestion(typed, name string, maxDistance int) bool {
+	typed = strings.ToLower(typed)
+	name = strings.ToLower(name)
+
+	if typed == "" {
+		return false
+	}
+
+	if strings.HasPrefix(name, typed) {
+		return true
+	}
+
+	return levenshtein(typed, name) <= maxDistance
+}
+
+func levenshtein(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(t)]
+}
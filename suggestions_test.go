@@ -0,0 +1,125 @@
+package gocli
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"version", "version", 0},
+		{"verison", "version", 2},
+		{"uppercas", "uppercase", 1},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCommand_SuggestionsFor(t *testing.T) {
+	tests := []struct {
+		name  string
+		typed string
+		opts  []CommandOption
+		want  []string
+	}{
+		{"typo", "uppercas", nil, []string{"uppercase"}},
+		{"transposition", "verison", nil, []string{"version"}},
+		{"prefix", "lowe", nil, []string{"lowercase"}},
+		{"alias typo", "uppr", nil, []string{"uppercase"}},
+		{"case insensitive", "VERSION", nil, []string{"version"}},
+		{"no match", "deploy", nil, []string{}},
+		{"tight distance", "verson", []CommandOption{WithSuggestionDistance(1)}, []string{"version"}},
+		{"too far for distance", "vrsn", []CommandOption{WithSuggestionDistance(1)}, []string{}},
+		{"disabled", "uppercas", []CommandOption{WithoutSuggestions()}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := newToolboxTree(nil, tt.opts...)
+
+			got := rootCmd.SuggestionsFor(tt.typed)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SuggestionsFor(%q) = %v, want %v", tt.typed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand_SuggestionDistanceInherited(t *testing.T) {
+	rootCmd := NewCommand(WithName("app"), WithSuggestionDistance(4))
+	groupCmd := NewCommand(WithName("db"))
+	groupCmd.AddCommand(NewCommand(WithName("migrate")))
+	rootCmd.AddCommand(groupCmd)
+
+	if got := groupCmd.SuggestionsFor("mgrt"); !reflect.DeepEqual(got, []string{"migrate"}) {
+		t.Errorf("expected inherited distance to suggest migrate, got %v", got)
+	}
+}
+
+func TestCommand_UnknownCommand(t *testing.T) {
+
+	t.Run("error with suggestion", func(t *testing.T) {
+		rootCmd := newToolboxTree(nil)
+		rootCmd.SetArgs([]string{"uppercas", "foo"})
+
+		err := rootCmd.Execute()
+
+		var unknownErr *UnknownCommandError
+		if !errors.As(err, &unknownErr) {
+			t.Fatalf("expected UnknownCommandError, got %v", err)
+		}
+		if unknownErr.Name != "uppercas" || unknownErr.Command != "toolbox" {
+			t.Errorf("unexpected error details: %+v", unknownErr)
+		}
+		if !strings.Contains(err.Error(), "Did you mean this?\n\tuppercase") {
+			t.Errorf("expected suggestion in message, got %q", err.Error())
+		}
+	})
+
+	t.Run("error without suggestion", func(t *testing.T) {
+		rootCmd := newToolboxTree(nil)
+		rootCmd.SetArgs([]string{"--", "deploy"})
+
+		err := rootCmd.Execute()
+		if err == nil || strings.Contains(err.Error(), "Did you mean") {
+			t.Errorf("expected plain unknown command error, got %v", err)
+		}
+	})
+
+	t.Run("root with run accepts positional args", func(t *testing.T) {
+		var gotArgs []string
+		rootCmd := newToolboxTree(nil, WithRun(func(cmd *Command, args []string) error {
+			gotArgs = args
+			return nil
+		}))
+		rootCmd.SetArgs([]string{"uppercas"})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if !reflect.DeepEqual(gotArgs, []string{"uppercas"}) {
+			t.Errorf("expected args [uppercas], got %v", gotArgs)
+		}
+	})
+
+	t.Run("no args is not an error", func(t *testing.T) {
+		rootCmd := newToolboxTree(nil)
+		rootCmd.SetArgs([]string{})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package gocli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gnemade360/go-config/configutil"
+	configerrors "github.com/gnemade360/go-config/errors"
+)
+
+// timeoutFor returns the command's timeout, preferring the configured key over
+// the value given to WithTimeout.
+func (c *Command) timeoutFor() (time.Duration, error) {
+	if c.timeoutKey == "" {
+		return c.timeout, nil
+	}
+
+	cfg := c.Config()
+	if cfg == nil {
+		return c.timeout, nil
+	}
+
+	timeout, err := configutil.GetDurationE(cfg, c.timeoutKey)
+	if err != nil {
+		var notFound *configerrors.ConfigNotFoundError
+		if errors.As(err, &notFound) {
+			return c.timeout, nil
+		}
+		return 0, fmt.Errorf("invalid timeout %q: %w", c.timeoutKey, err)
+	}
+
+	return timeout, nil
+}
+
+// lifecycleTimeout is the timeout applied to the current execution. ctx is
+// derived from parent with the command's deadline.
+type lifecycleTimeout struct {
+	timeout time.Duration
+	ctx     context.Context
+	parent  context.Context
+}
+
+// timeoutExceeded turns the error of a phase into a TimeoutError when the
+// command's own deadline expired. Phases that succeed, and deadlines or
+// cancellations coming from the caller's context, are left alone.
+func (c *Command) timeoutExceeded(phase string, err error) error {
+	t := c.activeTimeout
+	if err == nil || t == nil || !errors.Is(t.ctx.Err(), context.DeadlineExceeded) || t.parent.Err() != nil {
+		return nil
+	}
+
+	return &TimeoutError{
+		CommandPath: c.CommandPath(),
+		Phase:       phase,
+		Timeout:     t.timeout,
+		Err:         err,
+	}
+}
@@ -0,0 +1,217 @@
+package gocli
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func waitForDone(cmd *Command, args []string) error {
+	<-cmd.Context().Done()
+	return cmd.Context().Err()
+}
+
+func TestCommand_Timeout(t *testing.T) {
+	finallyExecuted := false
+
+	rootCmd := NewCommand(WithName("root"))
+	rootCmd.AddCommand(NewCommand(
+		WithName("sync"),
+		WithTimeout(10*time.Millisecond),
+		WithRun(waitForDone),
+		WithFinally(func(cmd *Command, args []string) error {
+			finallyExecuted = true
+			return nil
+		}),
+	))
+
+	rootCmd.SetArgs([]string{"sync"})
+
+	err := rootCmd.Execute()
+
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected TimeoutError, got %v", err)
+	}
+
+	if timeoutErr.CommandPath != "root sync" || timeoutErr.Phase != "run" || timeoutErr.Timeout != 10*time.Millisecond {
+		t.Errorf("unexpected timeout error fields: %+v", timeoutErr)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("expected the command's error to be wrapped")
+	}
+
+	if ExitCode(err) != ExitTimeout {
+		t.Errorf("expected exit code %d, got %d", ExitTimeout, ExitCode(err))
+	}
+
+	if !finallyExecuted {
+		t.Error("finally should run after a timeout")
+	}
+}
+
+func TestCommand_TimeoutStopsLaterPhases(t *testing.T) {
+	runExecuted := false
+
+	cmd := NewCommand(
+		WithName("test"),
+		WithTimeout(10*time.Millisecond),
+		WithPreRun(waitForDone),
+		WithRun(func(cmd *Command, args []string) error {
+			runExecuted = true
+			return nil
+		}),
+	)
+
+	cmd.SetArgs([]string{})
+
+	var timeoutErr *TimeoutError
+	if err := cmd.Execute(); !errors.As(err, &timeoutErr) || timeoutErr.Phase != "preRun" {
+		t.Fatalf("expected TimeoutError during preRun, got %v", err)
+	}
+
+	if runExecuted {
+		t.Error("run should not execute after the timeout expired")
+	}
+}
+
+func TestCommand_TimeoutNotExceeded(t *testing.T) {
+	var deadline time.Time
+
+	cmd := NewCommand(
+		WithName("test"),
+		WithTimeout(time.Minute),
+		WithRun(func(cmd *Command, args []string) error {
+			deadline, _ = cmd.Context().Deadline()
+			return nil
+		}),
+	)
+
+	cmd.SetArgs([]string{})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if deadline.IsZero() {
+		t.Error("expected a deadline on the command context")
+	}
+}
+
+func TestCommand_TimeoutOnlyReportsFailedPhases(t *testing.T) {
+	t.Run("phase succeeds after the deadline", func(t *testing.T) {
+		cmd := NewCommand(
+			WithName("app"),
+			WithTimeout(10*time.Millisecond),
+			WithRun(func(cmd *Command, args []string) error {
+				time.Sleep(30 * time.Millisecond)
+				return nil
+			}),
+		)
+		cmd.SetArgs([]string{})
+
+		if err := cmd.Execute(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("caller deadline", func(t *testing.T) {
+		cmd := NewCommand(
+			WithName("app"),
+			WithTimeout(time.Hour),
+			WithRun(waitForDone),
+		)
+		cmd.SetArgs([]string{})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		err := cmd.ExecuteContext(ctx)
+
+		var timeoutErr *TimeoutError
+		if errors.As(err, &timeoutErr) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected the caller's deadline error, got %v", err)
+		}
+	})
+
+	t.Run("hook replaces the context", func(t *testing.T) {
+		cmd := NewCommand(
+			WithName("app"),
+			WithTimeout(10*time.Millisecond),
+			WithPreRun(func(cmd *Command, args []string) error {
+				cmd.SetContext(context.WithValue(cmd.Context(), invocationKey{}, "value"))
+				return nil
+			}),
+			WithRun(func(cmd *Command, args []string) error {
+				cmd.SetContext(context.Background())
+				time.Sleep(30 * time.Millisecond)
+				return errors.New("stalled")
+			}),
+		)
+		cmd.SetArgs([]string{})
+
+		var timeoutErr *TimeoutError
+		if err := cmd.Execute(); !errors.As(err, &timeoutErr) || timeoutErr.Phase != "run" {
+			t.Errorf("expected TimeoutError during run, got %v", err)
+		}
+	})
+}
+
+func TestCommand_TimeoutFromConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		wantErr bool
+		timeout time.Duration
+	}{
+		{"config overrides default", map[string]interface{}{"sync.timeout": "10ms"}, true, 10 * time.Millisecond},
+		{"missing key uses default", map[string]interface{}{}, false, 0},
+		{"invalid value", map[string]interface{}{"sync.timeout": "soon"}, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd := NewCommand(
+				WithName("root"),
+				WithConfigProvider(&mapConfigProvider{values: tt.values}),
+			)
+			rootCmd.AddCommand(NewCommand(
+				WithName("sync"),
+				WithTimeout(time.Minute),
+				WithTimeoutKey("sync.timeout"),
+				WithRun(func(cmd *Command, args []string) error {
+					select {
+					case <-cmd.Context().Done():
+						return cmd.Context().Err()
+					case <-time.After(50 * time.Millisecond):
+						return nil
+					}
+				}),
+			))
+
+			rootCmd.SetArgs([]string{"sync"})
+
+			err := rootCmd.Execute()
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("Execute failed: %v", err)
+				}
+				return
+			}
+
+			var timeoutErr *TimeoutError
+			if tt.timeout > 0 {
+				if !errors.As(err, &timeoutErr) || timeoutErr.Timeout != tt.timeout {
+					t.Fatalf("expected TimeoutError after %s, got %v", tt.timeout, err)
+				}
+				return
+			}
+
+			if err == nil || errors.As(err, &timeoutErr) {
+				t.Fatalf("expected invalid timeout error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package gocli
+
+import (
+	"fmt"
+	"os"
+)
+
+const debugEnv = "GOCLI_DEBUG"
+
+func (c *Command) debugEnabled() bool {
+	if c.debug {
+		return true
+	}
+
+	if c.parent != nil {
+		return c.parent.debugEnabled()
+	}
+
+	return os.Getenv(debugEnv) != ""
+}
+
+// Validate checks the whole tree below c for structural problems and reports
+// all of them in a single ValidationError.
+func (c *Command) Validate() error {
+	problems := make([]string, 0)
+	c.validate(make(map[*Command]bool), &problems)
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Problems: problems}
+}
+
+func (c *Command) validate(visited map[*Command]bool, problems *[]string) {
+	if visited[c] {
+		return
+	}
+	visited[c] = true
+
+	report := func(format string, args ...interface{}) {
+		*problems = append(*problems, fmt.Sprintf(format, args...))
+	}
+
+	c.validatePositionals(report)
+	c.validateConfigKeys(report)
+
+	if c.onlyValidArgs && len(c.allowedArgs) == 0 {
+		report("%q: WithOnlyValidArgs is used without any allowed args", c.CommandPath())
+	}
+
+	owners := make(map[string]string)
+	register := func(key, description string) {
+		if owner, ok := owners[key]; ok {
+			report("%q: %s collides with %s", c.CommandPath(), description, owner)
+			return
+		}
+		owners[key] = description
+	}
+
+	for i, cmd := range c.commands {
+		if cmd.commandName == "" {
+			report("%q: subcommand %d has an empty name", c.CommandPath(), i)
+		} else {
+			register(cmd.commandName, fmt.Sprintf("command %q", cmd.commandName))
+		}
+
+		for _, alias := range cmd.aliases {
+			if alias != cmd.commandName {
+				register(alias, fmt.Sprintf("alias %q of %q", alias, cmd.commandName))
+			}
+		}
+
+		if cmd.parent != c {
+			report("%q: command %q was also added to %q", c.CommandPath(), cmd.commandName, cmd.parent.CommandPath())
+			continue
+		}
+
+		cmd.validate(visited, problems)
+	}
+}
@@ -0,0 +1,122 @@
+package gocli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCommand_ValidateValidTree(t *testing.T) {
+	rootCmd := NewCommand(WithName("app"))
+	rootCmd.AddCommand(
+		NewCommand(WithName("version"), WithAlias("v")),
+		NewCommand(
+			WithName("output"),
+			WithAllowedArgs("json", "yaml"),
+			WithOnlyValidArgs(),
+			WithArgValidator(ExactArgs(1)),
+		),
+	)
+
+	if err := rootCmd.Validate(); err != nil {
+		t.Errorf("expected a valid tree, got %v", err)
+	}
+}
+
+func TestCommand_ValidateReportsAllProblems(t *testing.T) {
+	rootCmd := NewCommand(WithName("app"))
+	otherRoot := NewCommand(WithName("other"))
+	shared := NewCommand(WithName("shared"))
+
+	groupCmd := NewCommand(WithName("group"))
+	groupCmd.AddCommand(
+		NewCommand(WithName("run")),
+		NewCommand(WithName("run")),
+	)
+
+	rootCmd.AddCommand(
+		NewCommand(WithName("version"), WithAlias("v", "ver")),
+		NewCommand(WithName("v")),
+		NewCommand(WithName("verify"), WithAlias("ver")),
+		NewCommand(),
+		NewCommand(WithName("output"), WithOnlyValidArgs(), WithArgValidator(ExactArgs(1))),
+		groupCmd,
+		shared,
+	)
+	otherRoot.AddCommand(shared)
+
+	err := rootCmd.Validate()
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+
+	expected := []string{
+		`"app": command "v" collides with alias "v" of "version"`,
+		`"app": alias "ver" of "verify" collides with alias "ver" of "version"`,
+		`"app": subcommand 3 has an empty name`,
+		`"app output": WithOnlyValidArgs is used without any allowed args`,
+		`"app group": command "run" collides with command "run"`,
+		`"app": command "shared" was also added to "other"`,
+	}
+
+	if len(validationErr.Problems) != len(expected) {
+		t.Errorf("expected %d problems, got %d:\n%s", len(expected), len(validationErr.Problems), err)
+	}
+
+	for _, want := range expected {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected problem %q, got:\n%s", want, err)
+		}
+	}
+}
+
+func TestCommand_ValidateInDebugMode(t *testing.T) {
+	newTree := func(opts ...CommandOption) (*Command, *bool) {
+		executed := false
+		rootCmd := NewCommand(append([]CommandOption{WithName("app")}, opts...)...)
+		rootCmd.AddCommand(
+			NewCommand(WithName("run"), WithRun(func(cmd *Command, args []string) error {
+				executed = true
+				return nil
+			})),
+			NewCommand(WithName("run")),
+		)
+		rootCmd.SetArgs([]string{"run"})
+		return rootCmd, &executed
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Setenv(debugEnv, "")
+		rootCmd, executed := newTree()
+
+		if err := rootCmd.Execute(); err != nil || !*executed {
+			t.Errorf("expected execution without validation, got %v", err)
+		}
+	})
+
+	t.Run("option", func(t *testing.T) {
+		t.Setenv(debugEnv, "")
+		rootCmd, executed := newTree(WithDebug())
+
+		var validationErr *ValidationError
+		if err := rootCmd.Execute(); !errors.As(err, &validationErr) {
+			t.Errorf("expected ValidationError, got %v", err)
+		}
+
+		if *executed {
+			t.Error("run should not execute for an invalid tree in debug mode")
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv(debugEnv, "1")
+		rootCmd, _ := newTree()
+
+		var validationErr *ValidationError
+		if err := rootCmd.Execute(); !errors.As(err, &validationErr) {
+			t.Errorf("expected ValidationError, got %v", err)
+		}
+	})
+}